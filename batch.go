@@ -0,0 +1,109 @@
+package rrule
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Expander is a recurrence that can be expanded over a window. Both *RRule
+// and *Set implement it.
+type Expander interface {
+	AppendBetween(dst []time.Time, after, before time.Time, inc bool) []time.Time
+}
+
+// limitedExpander is implemented by *RRule and *Set, whose expansion can
+// stop partway when the context is done.
+type limitedExpander interface {
+	limitedIterator(l *limiter) Next
+}
+
+// expand returns the occurrences of source between after and before. It
+// stops partway through sources of this package when ctx is done.
+func expand(ctx context.Context, source Expander, after, before time.Time, inc bool) []time.Time {
+	if s, ok := source.(limitedExpander); ok {
+		return appendBetween([]time.Time{}, s.limitedIterator(newLimiter(ctx, Limits{})), after, before, inc)
+	}
+	return source.AppendBetween([]time.Time{}, after, before, inc)
+}
+
+// Occurrence is an occurrence of one of the recurrences expanded by
+// MergeBetween.
+type Occurrence struct {
+	// Source is the index of the recurrence in the slice given to
+	// MergeBetween.
+	Source int
+	Time   time.Time
+}
+
+// ExpandBetween returns the occurrences of every source between after and
+// before, in the order of sources, as Between does. The sources are
+// expanded concurrently by workers goroutines, or by runtime.GOMAXPROCS(0)
+// if workers is not positive. It stops early and returns ctx.Err() if ctx is
+// done, also partway through the expansion of an RRule or a Set.
+// The sources must not be changed during the call.
+func ExpandBetween(ctx context.Context, sources []Expander, after, before time.Time, inc bool, workers int) ([][]time.Time, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(sources) {
+		workers = len(sources)
+	}
+
+	results := make([][]time.Time, len(sources))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = expand(ctx, sources[i], after, before, inc)
+			}
+		}()
+	}
+
+feed:
+	for i := range sources {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// MergeBetween expands sources as ExpandBetween does, then calls yield with
+// their occurrences merged in chronological order until yield returns false.
+// Simultaneous occurrences are yielded in the order of sources.
+func MergeBetween(ctx context.Context, sources []Expander, after, before time.Time, inc bool, workers int,
+	yield func(Occurrence) bool) error {
+	results, err := ExpandBetween(ctx, sources, after, before, inc, workers)
+	if err != nil {
+		return err
+	}
+	gens := make([]Next, len(results))
+	for i, times := range results {
+		gens[i] = timeSliceIterator(times)
+	}
+	h := newGenHeap(gens...)
+	for {
+		dt, source, ok := h.nextSource()
+		if !ok {
+			return nil
+		}
+		if !yield(Occurrence{Source: source, Time: dt}) {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
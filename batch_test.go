@@ -0,0 +1,98 @@
+package rrule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func batchSources(t *testing.T) []Expander {
+	r1, _ := NewRRule(ROption{Freq: DAILY, Dtstart: time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)})
+	r2, _ := NewRRule(ROption{Freq: WEEKLY, Dtstart: time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC)})
+	set, err := StrToRRuleSet("DTSTART:20200101T090000Z\nRRULE:FREQ=HOURLY;INTERVAL=12\nEXDATE:20200103T090000Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []Expander{r1, r2, set}
+}
+
+func TestExpandBetween(t *testing.T) {
+	sources := batchSources(t)
+	after, before := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+	for _, workers := range []int{0, 1, 2, 8} {
+		results, err := ExpandBetween(context.Background(), sources, after, before, true, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, source := range sources {
+			want := source.AppendBetween(nil, after, before, true)
+			if !timesEqual(results[i], want) {
+				t.Errorf("workers %d: source %d: get %v, want %v", workers, i, results[i], want)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ExpandBetween(ctx, sources, after, before, true, 2); err != context.Canceled {
+		t.Errorf("get %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestExpandBetweenCancel(t *testing.T) {
+	// BYSECOND=1 is never reached, so the expansion never ends by itself.
+	r, _ := NewRRule(ROption{Freq: SECONDLY, Interval: 2, Bysecond: []int{1},
+		Dtstart: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)})
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	done := make(chan error, 1)
+	go func() {
+		_, err := ExpandBetween(ctx, []Expander{r}, r.GetDTStart(), r.GetDTStart().AddDate(1, 0, 0), true, 1)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("get %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ExpandBetween did not stop once ctx was canceled")
+	}
+}
+
+func TestMergeBetween(t *testing.T) {
+	sources := batchSources(t)
+	after, before := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 3, 12, 0, 0, 0, time.UTC)
+	want := []Occurrence{
+		{0, time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{1, time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{2, time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{2, time.Date(2020, 1, 2, 21, 0, 0, 0, time.UTC)},
+		{0, time.Date(2020, 1, 3, 9, 0, 0, 0, time.UTC)},
+	}
+	var got []Occurrence
+	err := MergeBetween(context.Background(), sources, after, before, false, 2, func(o Occurrence) bool {
+		got = append(got, o)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("get %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i].Source != want[i].Source || !got[i].Time.Equal(want[i].Time) {
+			t.Errorf("get %v, want %v", got[i], want[i])
+		}
+	}
+
+	n := 0
+	MergeBetween(context.Background(), sources, after, before, false, 2, func(o Occurrence) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Errorf("get %d calls, want 2", n)
+	}
+}
@@ -0,0 +1,132 @@
+package rrule
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultCacheSize is the number of occurrences cached when
+// CacheOptions.MaxOccurrences is 0.
+const DefaultCacheSize = 10000
+
+// CacheOptions bounds the occurrences memoized by a cache. Occurrences past
+// the bounds are generated again on each iteration.
+type CacheOptions struct {
+	// MaxOccurrences is the number of leading occurrences kept. It defaults
+	// to DefaultCacheSize.
+	MaxOccurrences int
+	// Window, if not zero, stops caching at occurrences later than Window
+	// after the first one.
+	Window time.Duration
+}
+
+// occurrenceCache memoizes the leading occurrences of a generator, as they
+// are generated by the iterators it returns. It is safe for concurrent use.
+type occurrenceCache struct {
+	opts   CacheOptions
+	source func() Next
+
+	mu    sync.Mutex
+	times []time.Time
+	gen   Next // generator of the occurrences after times
+	// complete is true when times holds every occurrence.
+	complete bool
+	// full is true when the next occurrence is past the bounds.
+	full bool
+}
+
+func newOccurrenceCache(opts CacheOptions, source func() Next) *occurrenceCache {
+	if opts.MaxOccurrences <= 0 {
+		opts.MaxOccurrences = DefaultCacheSize
+	}
+	return &occurrenceCache{opts: opts, source: source}
+}
+
+// reset drops the cached occurrences. It is a no-op on a nil cache.
+func (c *occurrenceCache) reset() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.times, c.gen, c.complete, c.full = nil, nil, false, false
+	c.mu.Unlock()
+}
+
+// get returns the i-th occurrence if it is cached or can be cached, and
+// whether it exists. cached is false if the occurrence is past the bounds.
+func (c *occurrenceCache) get(i int) (value time.Time, ok, cached bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.times) <= i {
+		if c.complete {
+			return time.Time{}, false, true
+		}
+		if c.full {
+			return time.Time{}, false, false
+		}
+		if c.gen == nil {
+			c.gen = c.source()
+		}
+		v, ok := c.gen()
+		if !ok {
+			c.complete = true
+			c.gen = nil
+			continue
+		}
+		if len(c.times) >= c.opts.MaxOccurrences ||
+			c.opts.Window != 0 && len(c.times) != 0 && v.Sub(c.times[0]) > c.opts.Window {
+			c.full = true
+			c.gen = nil
+			continue
+		}
+		c.times = append(c.times, v)
+	}
+	return c.times[i], true, true
+}
+
+// iterator returns an iterator replaying the cached occurrences, then
+// generating the others from a fresh source once the cache is full.
+func (c *occurrenceCache) iterator() Next {
+	i := 0
+	var next Next
+	return func() (time.Time, bool) {
+		if next != nil {
+			return next()
+		}
+		v, ok, cached := c.get(i)
+		if cached {
+			i++
+			return v, ok
+		}
+		next = c.source()
+		for ; i > 0; i-- {
+			next()
+		}
+		return next()
+	}
+}
+
+// EnableCache memoizes the occurrences of the rule within the bounds of
+// opts, so that later iterations, including All, Between, Before and After,
+// replay them instead of generating them again. The cache is safe for
+// concurrent use and is invalidated by DTStart and Until.
+func (r *RRule) EnableCache(opts CacheOptions) {
+	r.cache = newOccurrenceCache(opts, r.iterator)
+}
+
+// DisableCache drops the cache of the rule.
+func (r *RRule) DisableCache() {
+	r.cache = nil
+}
+
+// EnableCache memoizes the occurrences of the set within the bounds of
+// opts, as RRule.EnableCache does. The cache is invalidated by the methods
+// changing the set, but not by changes made directly to its RRULE.
+func (set *Set) EnableCache(opts CacheOptions) {
+	set.cache = newOccurrenceCache(opts, set.iterator)
+}
+
+// DisableCache drops the cache of the set.
+func (set *Set) DisableCache() {
+	set.cache = nil
+}
@@ -0,0 +1,80 @@
+package rrule
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRRuleCache(t *testing.T) {
+	dtstart := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	r, _ := NewRRule(ROption{Freq: DAILY, Dtstart: dtstart, Count: 10})
+	want := r.All()
+	r.EnableCache(CacheOptions{MaxOccurrences: 4})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 3; i++ {
+				if v := r.After(dtstart.AddDate(0, 0, 2), false); !v.Equal(want[3]) {
+					t.Errorf("get %v, want %v", v, want[3])
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got := r.All(); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+	if n := len(r.cache.times); n != 4 {
+		t.Errorf("get %d cached occurrences, want 4", n)
+	}
+
+	r.DTStart(dtstart.AddDate(0, 0, 1))
+	if got := r.All(); len(got) != 10 || !got[0].Equal(want[1]) {
+		t.Errorf("get %v, want a cache invalidated by DTStart", got)
+	}
+	r.Until(dtstart.AddDate(0, 0, 3))
+	if got := r.All(); len(got) != 3 {
+		t.Errorf("get %v, want a cache invalidated by Until", got)
+	}
+}
+
+func TestCacheWindow(t *testing.T) {
+	dtstart := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	r, _ := NewRRule(ROption{Freq: HOURLY, Dtstart: dtstart, Count: 48})
+	r.EnableCache(CacheOptions{Window: 10 * time.Hour})
+	if got := r.All(); len(got) != 48 {
+		t.Errorf("get %d occurrences, want 48", len(got))
+	}
+	if n := len(r.cache.times); n != 11 {
+		t.Errorf("get %d cached occurrences, want 11", n)
+	}
+}
+
+func TestSetCache(t *testing.T) {
+	set, err := StrToRRuleSet("DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY;COUNT=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	set.EnableCache(CacheOptions{})
+	if got := set.All(); len(got) != 3 {
+		t.Errorf("get %v, want 3 occurrences", got)
+	}
+	set.ExDate(time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC))
+	set.RDate(time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC))
+	want := []time.Time{
+		time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC),
+	}
+	if got := set.All(); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+	set.DisableCache()
+	if got := set.All(); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,196 @@
+package rrule
+
+import (
+	"sort"
+	"time"
+)
+
+// Calendar tells working days from non-working days, e.g. weekends and
+// public holidays. The time of day is irrelevant.
+type Calendar interface {
+	IsWorkingDay(t time.Time) bool
+}
+
+// HolidayCalendar is a Calendar made of weekly days off and holidays. The
+// holidays are given as dates or as rules with one occurrence per holiday,
+// such as those returned by FixedHoliday, NthWeekdayHoliday and
+// EasterHoliday.
+type HolidayCalendar struct {
+	weekend []int
+	rules   []*RRule
+	dates   []time.Time
+}
+
+// NewHolidayCalendar returns a calendar without holidays whose days off are
+// weekend, or Saturday and Sunday if weekend is empty.
+func NewHolidayCalendar(weekend ...Weekday) *HolidayCalendar {
+	if len(weekend) == 0 {
+		weekend = []Weekday{SA, SU}
+	}
+	c := &HolidayCalendar{}
+	for _, w := range weekend {
+		c.weekend = append(c.weekend, w.weekday)
+	}
+	return c
+}
+
+// AddRule adds the occurrences of r as holidays. Only the date of the
+// occurrences matters, as long as r has at most one occurrence per day.
+func (c *HolidayCalendar) AddRule(r *RRule) {
+	c.rules = append(c.rules, r)
+}
+
+// AddDate adds the dates as holidays.
+func (c *HolidayCalendar) AddDate(dates ...time.Time) {
+	c.dates = append(c.dates, dates...)
+}
+
+// IsHoliday reports whether the date of t is a holiday, regardless of the
+// weekend.
+func (c *HolidayCalendar) IsHoliday(t time.Time) bool {
+	year, month, day := t.Date()
+	for _, date := range c.dates {
+		y, m, d := date.Date()
+		if y == year && m == month && d == day {
+			return true
+		}
+	}
+	for _, r := range c.rules {
+		hour, minute, second := r.dtstart.Clock()
+		if r.Contains(time.Date(year, month, day, hour, minute, second, r.dtstart.Nanosecond(), r.dtstart.Location())) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWorkingDay reports whether the date of t is neither a day off nor a
+// holiday.
+func (c *HolidayCalendar) IsWorkingDay(t time.Time) bool {
+	return !contains(c.weekend, toPyWeekday(t.Weekday())) && !c.IsHoliday(t)
+}
+
+// Between returns the holidays between after and before, as
+// RRule.Between does, without the days off of the weekend.
+func (c *HolidayCalendar) Between(after, before time.Time, inc bool) []time.Time {
+	gens := []Next{timeSliceIterator(sortedTimes(c.dates))}
+	for _, r := range c.rules {
+		gens = append(gens, r.Iterator())
+	}
+	h := newGenHeap(gens...)
+	var last time.Time
+	return between(func() (time.Time, bool) {
+		for {
+			dt, ok := h.next()
+			if !ok || last.IsZero() || !dt.Equal(last) {
+				last = dt
+				return dt, ok
+			}
+		}
+	}, after, before, inc)
+}
+
+// holidayStart and holidayEnd bound the holiday rules of this package to
+// the years of the Gregorian calendar.
+var (
+	holidayStart = time.Date(1583, 1, 1, 0, 0, 0, 0, time.UTC)
+	holidayEnd   = time.Date(MAXYEAR, 12, 31, 0, 0, 0, 0, time.UTC)
+)
+
+func holidayRule(option ROption) *RRule {
+	option.Freq = YEARLY
+	option.Dtstart = holidayStart
+	option.Until = holidayEnd
+	r := buildRRule(option)
+	return &r
+}
+
+// FixedHoliday returns a rule for a holiday on a fixed date, e.g. December
+// 25.
+func FixedHoliday(month time.Month, day int) *RRule {
+	return holidayRule(ROption{Bymonth: []int{int(month)}, Bymonthday: []int{day}})
+}
+
+// NthWeekdayHoliday returns a rule for a holiday on the nth weekday of a
+// month, e.g. NthWeekdayHoliday(time.May, MO.Nth(-1)) for the last Monday
+// of May.
+func NthWeekdayHoliday(month time.Month, weekday Weekday) *RRule {
+	return holidayRule(ROption{Bymonth: []int{int(month)}, Byweekday: []Weekday{weekday}})
+}
+
+// EasterHoliday returns a rule for a holiday offset days from Easter
+// Sunday, e.g. EasterHoliday(1) for Easter Monday.
+func EasterHoliday(offset int) *RRule {
+	return holidayRule(ROption{Byeaster: []int{offset}})
+}
+
+// workingDays returns the times of next on working days of cal.
+func workingDays(next Next, cal Calendar) Next {
+	return func() (time.Time, bool) {
+		for {
+			dt, ok := next()
+			if !ok || cal.IsWorkingDay(dt) {
+				return dt, ok
+			}
+		}
+	}
+}
+
+// WorkingDays returns an iterator over the occurrences of r on working days
+// of cal. With setpos, it instead selects in every period of the rule's
+// frequency the occurrences at the given positions among those on working
+// days, as BYSETPOS does. For instance, the third working day of every
+// month is setpos 3 of FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR with a calendar
+// of public holidays. r itself should not have a BYSETPOS.
+func (r *RRule) WorkingDays(cal Calendar, setpos ...int) Next {
+	next := workingDays(r.Iterator(), cal)
+	if len(setpos) == 0 {
+		return next
+	}
+
+	dt, ok := next()
+	var selected []time.Time
+	return func() (time.Time, bool) {
+		for len(selected) == 0 {
+			if !ok {
+				return time.Time{}, false
+			}
+			var period []time.Time
+			start, _ := r.periodStart(dt)
+			for ok {
+				if s, _ := r.periodStart(dt); !s.Equal(start) {
+					break
+				}
+				period = append(period, dt)
+				dt, ok = next()
+			}
+			selected = selectPositions(period, setpos)
+		}
+		result := selected[0]
+		selected = selected[1:]
+		return result, true
+	}
+}
+
+// selectPositions returns the sorted times at the 1-based positions setpos
+// in times, counting from the end for negative positions.
+func selectPositions(times []time.Time, setpos []int) []time.Time {
+	var result []time.Time
+	for _, pos := range setpos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(times) + pos
+		}
+		if i >= 0 && i < len(times) && !timeContains(result, times[i]) {
+			result = append(result, times[i])
+		}
+	}
+	sort.Sort(timeSlice(result))
+	return result
+}
+
+// WorkingDays returns an iterator over the occurrences of the set on
+// working days of cal.
+func (set *Set) WorkingDays(cal Calendar) Next {
+	return workingDays(set.Iterator(), cal)
+}
@@ -0,0 +1,88 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func testCalendar() *HolidayCalendar {
+	cal := NewHolidayCalendar()
+	cal.AddRule(FixedHoliday(time.January, 1))
+	cal.AddRule(EasterHoliday(-2))
+	cal.AddRule(EasterHoliday(1))
+	cal.AddRule(NthWeekdayHoliday(time.May, MO.Nth(-1)))
+	cal.AddDate(time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC))
+	return cal
+}
+
+func TestHolidayCalendar(t *testing.T) {
+	cal := testCalendar()
+	tests := []struct {
+		date    time.Time
+		working bool
+	}{
+		{time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC), false},
+		{time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC), true},
+		{time.Date(2020, 1, 4, 9, 0, 0, 0, time.UTC), false},
+		{time.Date(2020, 4, 10, 9, 0, 0, 0, time.UTC), false},
+		{time.Date(2020, 4, 13, 9, 0, 0, 0, time.UTC), false},
+		{time.Date(2020, 4, 14, 9, 0, 0, 0, time.UTC), true},
+		{time.Date(2020, 5, 25, 9, 0, 0, 0, time.UTC), false},
+		{time.Date(2020, 6, 2, 23, 0, 0, 0, time.UTC), false},
+	}
+	for _, test := range tests {
+		if got := cal.IsWorkingDay(test.date); got != test.working {
+			t.Errorf("%v: get %v, want %v", test.date, got, test.working)
+		}
+	}
+
+	want := []time.Time{
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 4, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 4, 13, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 5, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC),
+	}
+	if got := cal.Between(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), true); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+}
+
+func TestWorkingDays(t *testing.T) {
+	cal := testCalendar()
+	monthly, _ := NewRRule(ROption{Freq: MONTHLY, Byweekday: []Weekday{MO, TU, WE, TH, FR},
+		Dtstart: time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)})
+	third := monthly.WorkingDays(cal, 3)
+	want := []time.Time{
+		time.Date(2020, 1, 6, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 2, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 3, 4, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 4, 3, 9, 0, 0, 0, time.UTC),
+	}
+	for _, w := range want {
+		if got, _ := third(); got != w {
+			t.Errorf("get %v, want %v", got, w)
+		}
+	}
+
+	// The last working day of April 2020 is the 30th, of May the 29th.
+	last := monthly.WorkingDays(cal, -1)
+	for _, w := range []time.Time{
+		time.Date(2020, 1, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 2, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 4, 30, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 5, 29, 9, 0, 0, 0, time.UTC),
+	} {
+		if got, _ := last(); got != w {
+			t.Errorf("get %v, want %v", got, w)
+		}
+	}
+
+	set := &Set{}
+	daily, _ := NewRRule(ROption{Freq: DAILY, Count: 14, Dtstart: time.Date(2020, 4, 6, 9, 0, 0, 0, time.UTC)})
+	set.RRule(daily)
+	if got := all(set.WorkingDays(cal)); len(got) != 8 {
+		t.Errorf("get %v, want 8 working days", got)
+	}
+}
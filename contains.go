@@ -0,0 +1,134 @@
+package rrule
+
+import "time"
+
+// Contains reports whether dt is an occurrence of the RRule.
+// It checks dt against the BY* parts of the rule for the year and month of
+// dt and against the INTERVAL alignment, without iterating from DTSTART,
+// except to count occurrences when the rule has a COUNT, and to evaluate
+// BYSETPOS over the period of dt.
+func (r *RRule) Contains(dt time.Time) bool {
+	loc := r.dtstart.Location()
+	dt = dt.In(loc)
+	if dt.Before(r.dtstart) || dt.After(r.until) {
+		return false
+	}
+
+	hour, minute, second := dt.Clock()
+	if dt.Nanosecond() != r.dtstart.Nanosecond() ||
+		len(r.byhour) != 0 && !contains(r.byhour, hour) ||
+		len(r.byminute) != 0 && !contains(r.byminute, minute) ||
+		len(r.bysecond) != 0 && !contains(r.bysecond, second) {
+		return false
+	}
+	if pymod(r.periodIndex(dt), r.interval) != 0 {
+		return false
+	}
+
+	if len(r.bysetpos) != 0 {
+		if !r.containsInPeriod(dt) {
+			return false
+		}
+	} else {
+		ii := iterInfo{rrule: r}
+		ii.rebuild(dt.Year(), dt.Month())
+		if ii.excluded(dt.YearDay() - 1) {
+			return false
+		}
+	}
+
+	if r.count != 0 {
+		_, ok := r.IndexOf(dt)
+		return ok
+	}
+	return true
+}
+
+// periodIndex returns the number of periods of the rule's frequency between
+// DTSTART and dt, counted on the wall clock of DTSTART's location.
+func (r *RRule) periodIndex(dt time.Time) int {
+	start := r.dtstart
+	switch r.freq {
+	case YEARLY:
+		return dt.Year() - start.Year()
+	case MONTHLY:
+		return (dt.Year()-start.Year())*12 + int(dt.Month()-start.Month())
+	case WEEKLY:
+		week, _ := r.periodStart(dt)
+		first, _ := r.periodStart(start)
+		return (civilDays(week) - civilDays(first)) / 7
+	}
+	index := civilDays(dt) - civilDays(start)
+	if r.freq == DAILY {
+		return index
+	}
+	index = index*24 + dt.Hour() - start.Hour()
+	if r.freq == HOURLY {
+		return index
+	}
+	index = index*60 + dt.Minute() - start.Minute()
+	if r.freq == MINUTELY {
+		return index
+	}
+	return index*60 + dt.Second() - start.Second()
+}
+
+// periodStart returns the beginning of the period of the rule's frequency
+// that contains dt, and the beginning of the next period.
+func (r *RRule) periodStart(dt time.Time) (start, next time.Time) {
+	year, month, day := dt.Date()
+	hour, minute, second := dt.Clock()
+	loc := dt.Location()
+	switch r.freq {
+	case YEARLY:
+		start = time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(1, 0, 0)
+	case MONTHLY:
+		start = time.Date(year, month, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 1, 0)
+	case WEEKLY:
+		day -= pymod(toPyWeekday(dt.Weekday())-r.wkst, 7)
+		start = time.Date(year, month, day, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 0, 7)
+	case DAILY:
+		start = time.Date(year, month, day, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 0, 1)
+	case HOURLY:
+		start = time.Date(year, month, day, hour, 0, 0, 0, loc)
+		return start, start.Add(time.Hour)
+	case MINUTELY:
+		start = time.Date(year, month, day, hour, minute, 0, 0, loc)
+		return start, start.Add(time.Minute)
+	}
+	start = time.Date(year, month, day, hour, minute, second, 0, loc)
+	return start, start.Add(time.Second)
+}
+
+// containsInPeriod iterates over the period of the rule's frequency that
+// contains dt, which BYSETPOS selects from as a whole.
+func (r *RRule) containsInPeriod(dt time.Time) bool {
+	start, next := r.periodStart(dt)
+	if r.freq == WEEKLY && start.Before(r.dtstart) {
+		// The first week starts at DTSTART.
+		year, month, day := r.dtstart.Date()
+		start = time.Date(year, month, day, 0, 0, 0, 0, start.Location())
+	}
+	option := r.Options
+	option.Dtstart = start.Add(time.Duration(r.dtstart.Nanosecond()))
+	option.Until = next.Add(-time.Nanosecond)
+	option.Interval, option.Count = 1, 0
+	option.Byhour, option.Byminute, option.Bysecond = r.byhour, r.byminute, r.bysecond
+	period := buildRRule(option)
+	return period.After(dt, true).Equal(dt)
+}
+
+// Contains reports whether dt is an occurrence of the set: an RDATE or an
+// occurrence of the RRULE that no EXDATE excludes.
+func (set *Set) Contains(dt time.Time) bool {
+	for _, exdate := range set.exdate {
+		if exdate.Equal(set.truncate(dt)) {
+			return false
+		}
+	}
+	return timeContains(set.rdate, dt) || set.rrule != nil && set.rrule.Contains(dt)
+}
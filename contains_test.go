@@ -0,0 +1,69 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContains(t *testing.T) {
+	rules := []string{
+		"DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY;INTERVAL=3;COUNT=20",
+		"DTSTART:20200102T090000Z\nRRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;WKST=SU;UNTIL=20200601T000000Z",
+		"DTSTART:20200131T093000Z\nRRULE:FREQ=MONTHLY;BYMONTHDAY=31,-3;BYHOUR=9,17",
+		"DTSTART:20200101T090000Z\nRRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"DTSTART:20200108T090000Z\nRRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;BYSETPOS=1",
+		"DTSTART;TZID=America/New_York:20200301T013000\nRRULE:FREQ=HOURLY;INTERVAL=5;BYHOUR=1,2,3,11,16",
+		"DTSTART:20200101T000000Z\nRRULE:FREQ=YEARLY;BYWEEKNO=1,-1;BYDAY=MO",
+		"DTSTART:20200101T000000Z\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+		"DTSTART:20200101T120000Z\nRRULE:FREQ=YEARLY;BYEASTER=0,1",
+		"DTSTART:20200101T090000Z\nRRULE:FREQ=MINUTELY;INTERVAL=7;BYHOUR=9;COUNT=30",
+	}
+	for _, rule := range rules {
+		set, err := StrToRRuleSet(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := set.GetRRule()
+		start := r.GetDTStart()
+		end := start.AddDate(1, 0, 0)
+		occurrences := map[time.Time]bool{}
+		for _, v := range r.Between(start, end, true) {
+			occurrences[v.UTC()] = true
+		}
+		var candidates []time.Time
+		for v := range occurrences {
+			candidates = append(candidates, v, v.Add(time.Second), v.AddDate(0, 0, -7))
+		}
+		for v := start.Add(-time.Hour); v.Before(end); v = v.Add(time.Hour + time.Minute) {
+			candidates = append(candidates, v)
+		}
+		for _, v := range candidates {
+			if got, want := r.Contains(v), occurrences[v.UTC()] && v.Before(end); v.Before(end) && got != want {
+				t.Errorf("%q: Contains(%v) = %v, want %v", rule, v, got, want)
+			}
+		}
+	}
+}
+
+func TestSetContains(t *testing.T) {
+	set, err := StrToRRuleSet("DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY;COUNT=5\nRDATE:20191231T090000Z\nEXDATE:20200102T090000Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		dt   time.Time
+		want bool
+	}{
+		{time.Date(2019, 12, 31, 9, 0, 0, 0, time.UTC), true},
+		{time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC), true},
+		{time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC), false},
+		{time.Date(2020, 1, 5, 9, 0, 0, 0, time.UTC), true},
+		{time.Date(2020, 1, 6, 9, 0, 0, 0, time.UTC), false},
+		{time.Date(2020, 1, 3, 9, 0, 0, 1, time.UTC), false},
+	}
+	for _, c := range cases {
+		if got := set.Contains(c.dt); got != c.want {
+			t.Errorf("Contains(%v) = %v, want %v", c.dt, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package rrule
+
+import "time"
+
+// Count returns the number of occurrences of the RRule. A rule without
+// COUNT and UNTIL ends 290 years after DTSTART.
+// Simple DAILY and WEEKLY rules are counted without iterating.
+func (r *RRule) Count() int {
+	if a, ok := r.arithmetic(); ok {
+		return a.total
+	}
+	return count(r.Iterator())
+}
+
+// CountBetween returns the number of occurrences between after and before.
+// The inc keyword has the same meaning as in Between.
+func (r *RRule) CountBetween(after, before time.Time, inc bool) int {
+	if a, ok := r.arithmetic(); ok {
+		if n := a.countBefore(before, inc) - a.countBefore(after, !inc); n > 0 {
+			return n
+		}
+		return 0
+	}
+	return countBetween(r.Iterator(), after, before, inc)
+}
+
+// Nth returns the occurrence at the 0-based index i, or false if the rule
+// has no such occurrence.
+func (r *RRule) Nth(i int) (time.Time, bool) {
+	if a, ok := r.arithmetic(); ok {
+		if i < 0 || i >= a.total {
+			return time.Time{}, false
+		}
+		return a.at(i), true
+	}
+	return nth(r.Iterator(), i)
+}
+
+// IndexOf returns the 0-based index of the occurrence dt, or false if dt is
+// not an occurrence.
+func (r *RRule) IndexOf(dt time.Time) (int, bool) {
+	if a, ok := r.arithmetic(); ok {
+		i := a.countBefore(dt, false)
+		if i < a.total && a.at(i).Equal(dt) {
+			return i, true
+		}
+		return 0, false
+	}
+	return indexOf(r.Iterator(), dt)
+}
+
+// arithmetic describes a rule whose occurrences are evenly spaced in days,
+// i.e. the i-th one is DTSTART plus i*step days in the location of DTSTART.
+type arithmetic struct {
+	dtstart time.Time
+	step    int
+	total   int
+}
+
+// arithmetic returns the arithmetic description of DAILY and WEEKLY rules
+// without BY* parts.
+func (r *RRule) arithmetic() (arithmetic, bool) {
+	o := r.OrigOptions
+	if r.freq != DAILY && r.freq != WEEKLY ||
+		len(o.Bysetpos) != 0 || len(o.Bymonth) != 0 || len(o.Bymonthday) != 0 ||
+		len(o.Byyearday) != 0 || len(o.Byweekno) != 0 || len(o.Byweekday) != 0 ||
+		len(o.Byhour) != 0 || len(o.Byminute) != 0 || len(o.Bysecond) != 0 ||
+		len(o.Byeaster) != 0 {
+		return arithmetic{}, false
+	}
+	a := arithmetic{dtstart: r.dtstart, step: r.interval}
+	if r.freq == WEEKLY {
+		a.step *= 7
+	}
+
+	limit := r.until
+	if limit.Before(r.dtstart) {
+		return a, true
+	}
+	i := (civilDays(limit.In(r.dtstart.Location())) - civilDays(r.dtstart)) / a.step
+	if a.at(i).After(limit) {
+		i--
+	}
+	a.total = i + 1
+	if r.count != 0 && r.count < a.total {
+		a.total = r.count
+	}
+	return a, true
+}
+
+func (a arithmetic) at(i int) time.Time {
+	return a.dtstart.AddDate(0, 0, i*a.step)
+}
+
+// countBefore returns the number of occurrences before dt, or at or before
+// dt if inc is true.
+func (a arithmetic) countBefore(dt time.Time, inc bool) int {
+	before := func(v time.Time) bool {
+		return v.Before(dt) || inc && v.Equal(dt)
+	}
+	i, _ := divmod(civilDays(dt.In(a.dtstart.Location()))-civilDays(a.dtstart), a.step)
+	i++
+	if i < 0 {
+		i = 0
+	} else if i > a.total {
+		i = a.total
+	}
+	for i > 0 && !before(a.at(i-1)) {
+		i--
+	}
+	for i < a.total && before(a.at(i)) {
+		i++
+	}
+	return i
+}
+
+// civilDays returns the number of days from 1970-01-01 to the date of t.
+func civilDays(t time.Time) int {
+	y, m, d := t.Date()
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// Count returns the number of occurrences of the set.
+func (set *Set) Count() int {
+	if r, ok := set.onlyRRule(); ok {
+		return r.Count()
+	}
+	return count(set.Iterator())
+}
+
+// CountBetween returns the number of occurrences between after and before.
+// The inc keyword has the same meaning as in Between.
+func (set *Set) CountBetween(after, before time.Time, inc bool) int {
+	if r, ok := set.onlyRRule(); ok {
+		return r.CountBetween(after, before, inc)
+	}
+	return countBetween(set.Iterator(), after, before, inc)
+}
+
+// Nth returns the occurrence at the 0-based index i, or false if the set
+// has no such occurrence.
+func (set *Set) Nth(i int) (time.Time, bool) {
+	if r, ok := set.onlyRRule(); ok {
+		return r.Nth(i)
+	}
+	return nth(set.Iterator(), i)
+}
+
+// IndexOf returns the 0-based index of the occurrence dt, or false if dt is
+// not an occurrence.
+func (set *Set) IndexOf(dt time.Time) (int, bool) {
+	if r, ok := set.onlyRRule(); ok {
+		return r.IndexOf(dt)
+	}
+	return indexOf(set.Iterator(), dt)
+}
+
+// onlyRRule returns the RRULE of a set without RDATE and EXDATE.
+func (set *Set) onlyRRule() (*RRule, bool) {
+	return set.rrule, set.rrule != nil && len(set.rdate) == 0 && len(set.exdate) == 0
+}
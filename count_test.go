@@ -0,0 +1,76 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCount(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dtstart := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	options := []ROption{
+		{Freq: DAILY, Dtstart: dtstart, Count: 10},
+		{Freq: DAILY, Dtstart: dtstart, Interval: 3, Until: time.Date(2020, 3, 1, 8, 0, 0, 0, time.UTC)},
+		{Freq: DAILY, Dtstart: dtstart, Interval: 3, Until: time.Date(2020, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{Freq: WEEKLY, Dtstart: time.Date(2020, 1, 1, 2, 30, 0, 0, ny), Interval: 2, Until: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Freq: WEEKLY, Dtstart: dtstart, Until: dtstart.Add(-time.Hour)},
+		{Freq: DAILY, Dtstart: time.Date(MAXYEAR, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{Freq: WEEKLY, Dtstart: dtstart, Interval: 5},
+		{Freq: MONTHLY, Dtstart: dtstart, Bymonthday: []int{1, -1}, Count: 20},
+	}
+	after, before := time.Date(2020, 1, 10, 9, 0, 0, 0, time.UTC), time.Date(2020, 2, 14, 9, 0, 0, 0, time.UTC)
+	for _, option := range options {
+		r, err := NewRRule(option)
+		if err != nil {
+			t.Fatal(err)
+		}
+		all := r.All()
+		if n := r.Count(); n != len(all) {
+			t.Errorf("%v: Count() = %d, want %d", r, n, len(all))
+		}
+		for _, inc := range []bool{false, true} {
+			if n, want := r.CountBetween(after, before, inc), len(r.Between(after, before, inc)); n != want {
+				t.Errorf("%v: CountBetween(%v) = %d, want %d", r, inc, n, want)
+			}
+		}
+		for i, want := range all {
+			if v, ok := r.Nth(i); !ok || !v.Equal(want) {
+				t.Errorf("%v: Nth(%d) = %v, want %v", r, i, v, want)
+			}
+			if j, ok := r.IndexOf(want); !ok || j != i {
+				t.Errorf("%v: IndexOf(%v) = %d, want %d", r, want, j, i)
+			}
+			if _, ok := r.IndexOf(want.Add(time.Minute)); ok {
+				t.Errorf("%v: IndexOf(%v) = true, want false", r, want.Add(time.Minute))
+			}
+		}
+		if _, ok := r.Nth(len(all)); ok {
+			t.Errorf("%v: Nth(%d) = true, want false", r, len(all))
+		}
+	}
+}
+
+func TestSetCount(t *testing.T) {
+	set, err := StrToRRuleSet("DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY;COUNT=5\nRDATE:20191231T090000Z\nEXDATE:20200102T090000Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := set.Count(); n != 5 {
+		t.Errorf("get %d, want 5", n)
+	}
+	if n := set.CountBetween(time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC), time.Date(2020, 1, 4, 9, 0, 0, 0, time.UTC), true); n != 3 {
+		t.Errorf("get %d, want 3", n)
+	}
+	if v, ok := set.Nth(1); !ok || !v.Equal(time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("get %v, want 2020-01-01", v)
+	}
+	if i, ok := set.IndexOf(time.Date(2020, 1, 3, 9, 0, 0, 0, time.UTC)); !ok || i != 2 {
+		t.Errorf("get %d, want 2", i)
+	}
+	if _, ok := set.IndexOf(time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC)); ok {
+		t.Error("get true, want false for an EXDATE")
+	}
+}
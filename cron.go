@@ -0,0 +1,445 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoCronEquivalent is returned, wrapped with the reason, when a rule or a
+// cron expression cannot be expressed in the other notation.
+var ErrNoCronEquivalent = errors.New("no cron equivalent")
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronSecond = cronField{name: "second", min: 0, max: 59}
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day-of-month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12}}
+	cronDow = cronField{name: "day-of-week", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}}
+)
+
+var cronMacros = map[string]string{
+	"@YEARLY":   "0 0 1 1 *",
+	"@ANNUALLY": "0 0 1 1 *",
+	"@MONTHLY":  "0 0 1 * *",
+	"@WEEKLY":   "0 0 * * 0",
+	"@DAILY":    "0 0 * * *",
+	"@MIDNIGHT": "0 0 * * *",
+	"@HOURLY":   "0 * * * *",
+}
+
+// value parses a single number or name of the field.
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[s]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s value: %s", f.name, s)
+	}
+	return v, nil
+}
+
+// parse parses a list of values, ranges and steps of the field. It returns
+// all == true for "*" and "?".
+func (f cronField) parse(s string) (values []int, all bool, err error) {
+	if s == "*" || s == "?" {
+		return nil, true, nil
+	}
+	seen := map[int]bool{}
+	for _, item := range strings.Split(s, ",") {
+		step := 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step < 1 {
+				return nil, false, fmt.Errorf("invalid %s step: %s", f.name, item)
+			}
+			item = item[:i]
+		}
+		var lo, hi int
+		switch {
+		case item == "*":
+			lo, hi = f.min, f.max
+		case strings.Contains(item, "-"):
+			bounds := strings.SplitN(item, "-", 2)
+			if lo, err = f.value(bounds[0]); err != nil {
+				return nil, false, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return nil, false, err
+			}
+			if hi < lo {
+				return nil, false, fmt.Errorf("invalid %s range: %s", f.name, item)
+			}
+		default:
+			if lo, err = f.value(item); err != nil {
+				return nil, false, err
+			}
+			hi = lo
+			if step > 1 {
+				hi = f.max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			if !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+	}
+	sort.Ints(values)
+	return values, false, nil
+}
+
+// fromCronWeekday converts a cron day of week (0 or 7 for Sunday) to a Weekday.
+func fromCronWeekday(v int) Weekday {
+	return Weekday{weekday: (v + 6) % 7}
+}
+
+// toCronWeekday converts a Weekday to a cron day of week (0 for Sunday).
+func toCronWeekday(wday Weekday) int {
+	return (wday.weekday + 1) % 7
+}
+
+// CronToROption converts a standard cron expression to ROption starting at
+// dtstart.
+//
+// Both 5-field ("minute hour day-of-month month day-of-week") and 6-field
+// expressions with a leading second field are accepted, as well as the
+// @yearly, @monthly, @weekly, @daily and @hourly macros. Fields support
+// lists, ranges, steps and month and weekday names. The day-of-month field
+// also accepts L (last day), L-n (n days before the last day), 1W (first
+// weekday) and LW (last weekday); the day-of-week field accepts nL (last
+// given weekday of the month) and n#k (k-th given weekday of the month).
+//
+// Cron matches a day when either day field matches, while RRULE requires
+// both, so restricting both day fields returns ErrNoCronEquivalent, as does
+// nW with n other than 1.
+func CronToROption(expr string, dtstart time.Time) (*ROption, error) {
+	expr = strings.ToUpper(strings.TrimSpace(expr))
+	if macro, ok := cronMacros[expr]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron expression must have 5 or 6 fields: %q", expr)
+	}
+
+	seconds, allSeconds, err := cronSecond.parse(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	minutes, allMinutes, err := cronMinute.parse(fields[1])
+	if err != nil {
+		return nil, err
+	}
+	hours, allHours, err := cronHour.parse(fields[2])
+	if err != nil {
+		return nil, err
+	}
+	months, _, err := cronMonth.parse(fields[4])
+	if err != nil {
+		return nil, err
+	}
+
+	// Day of month, with the L and W extensions.
+	var monthdays []int
+	var weekdayPos int
+	allDom := fields[3] == "*" || fields[3] == "?"
+	if !allDom {
+		for _, item := range strings.Split(fields[3], ",") {
+			switch {
+			case item == "L":
+				monthdays = append(monthdays, -1)
+			case item == "LW" || item == "1W":
+				if len(fields[3]) != len(item) {
+					return nil, fmt.Errorf("%w: %s cannot be combined with other days", ErrNoCronEquivalent, item)
+				}
+				weekdayPos = 1
+				if item == "LW" {
+					weekdayPos = -1
+				}
+			case strings.HasSuffix(item, "W"):
+				return nil, fmt.Errorf("%w: nearest weekday %s", ErrNoCronEquivalent, item)
+			case strings.HasPrefix(item, "L-"):
+				n, err := strconv.Atoi(item[2:])
+				if err != nil || n < 0 || n > 30 {
+					return nil, fmt.Errorf("invalid day-of-month value: %s", item)
+				}
+				monthdays = append(monthdays, -n-1)
+			default:
+				values, _, err := cronDom.parse(item)
+				if err != nil {
+					return nil, err
+				}
+				monthdays = append(monthdays, values...)
+			}
+		}
+	}
+
+	// Day of week, with the L and # extensions.
+	var weekdays []Weekday
+	allDow := fields[5] == "*" || fields[5] == "?"
+	if !allDow {
+		for _, item := range strings.Split(fields[5], ",") {
+			switch {
+			case strings.Contains(item, "#"):
+				parts := strings.SplitN(item, "#", 2)
+				v, err := cronDow.value(parts[0])
+				if err != nil {
+					return nil, err
+				}
+				n, err := strconv.Atoi(parts[1])
+				if err != nil || n < 1 || n > 5 {
+					return nil, fmt.Errorf("invalid day-of-week value: %s", item)
+				}
+				wday := fromCronWeekday(v)
+				weekdays = append(weekdays, wday.Nth(n))
+			case len(item) > 1 && strings.HasSuffix(item, "L"):
+				v, err := cronDow.value(item[:len(item)-1])
+				if err != nil {
+					return nil, err
+				}
+				wday := fromCronWeekday(v)
+				weekdays = append(weekdays, wday.Nth(-1))
+			default:
+				values, _, err := cronDow.parse(item)
+				if err != nil {
+					return nil, err
+				}
+				for _, v := range values {
+					wday := fromCronWeekday(v)
+					if !containsWeekday(weekdays, wday) {
+						weekdays = append(weekdays, wday)
+					}
+				}
+			}
+		}
+	}
+	if !allDom && !allDow {
+		return nil, fmt.Errorf("%w: day-of-month and day-of-week are both restricted", ErrNoCronEquivalent)
+	}
+
+	option := ROption{Dtstart: dtstart, Bymonth: months, Bymonthday: monthdays, Byweekday: weekdays}
+	monthly := weekdayPos != 0
+	for _, wday := range weekdays {
+		if wday.n != 0 {
+			monthly = true
+		}
+	}
+	if monthly {
+		// Nth weekdays only make sense within a month, so every time field
+		// is spelled out.
+		option.Freq = MONTHLY
+		if allHours {
+			hours = rang(0, 24)
+		}
+		if allMinutes {
+			minutes = rang(0, 60)
+		}
+		if allSeconds {
+			seconds = rang(0, 60)
+		}
+		option.Byhour, option.Byminute, option.Bysecond = hours, minutes, seconds
+		if weekdayPos != 0 {
+			times := len(hours) * len(minutes) * len(seconds)
+			if times > 366 {
+				return nil, fmt.Errorf("%w: too many times per day for %s", ErrNoCronEquivalent, fields[3])
+			}
+			option.Byweekday = []Weekday{MO, TU, WE, TH, FR}
+			for i := 1; i <= times; i++ {
+				option.Bysetpos = append(option.Bysetpos, weekdayPos*i)
+			}
+			if weekdayPos < 0 {
+				sort.Ints(option.Bysetpos)
+			}
+		}
+		return &option, nil
+	}
+
+	// The finest unrestricted field sets the frequency.
+	switch {
+	case allSeconds:
+		option.Freq = SECONDLY
+	case allMinutes:
+		option.Freq = MINUTELY
+	case allHours:
+		option.Freq = HOURLY
+	default:
+		option.Freq = DAILY
+	}
+	option.Byhour, option.Byminute, option.Bysecond = hours, minutes, seconds
+	return &option, nil
+}
+
+// CronToRRule converts a cron expression to RRule starting at dtstart.
+// See CronToROption for the supported syntax.
+func CronToRRule(expr string, dtstart time.Time) (*RRule, error) {
+	option, err := CronToROption(expr, dtstart)
+	if err != nil {
+		return nil, err
+	}
+	return NewRRule(*option)
+}
+
+// CronString returns the cron expression equivalent to the rule.
+// A 5-field expression is returned when every occurrence falls on second 0,
+// otherwise a 6-field expression with a leading second field.
+//
+// ErrNoCronEquivalent is returned for rules cron cannot express, such as
+// rules with COUNT, UNTIL, BYSETPOS, BYYEARDAY, BYWEEKNO or BYEASTER, an
+// INTERVAL that does not divide the enclosing period (e.g. INTERVAL=3 with
+// WEEKLY), or both BYMONTHDAY and BYDAY.
+func (option *ROption) CronString() (string, error) {
+	if err := validateBounds(*option); err != nil {
+		return "", err
+	}
+	r := buildRRule(*option)
+	switch {
+	case r.count != 0:
+		return "", fmt.Errorf("%w: COUNT", ErrNoCronEquivalent)
+	case !option.Until.IsZero():
+		return "", fmt.Errorf("%w: UNTIL", ErrNoCronEquivalent)
+	case len(r.bysetpos) != 0:
+		return "", fmt.Errorf("%w: BYSETPOS", ErrNoCronEquivalent)
+	case len(r.byyearday) != 0:
+		return "", fmt.Errorf("%w: BYYEARDAY", ErrNoCronEquivalent)
+	case len(r.byweekno) != 0:
+		return "", fmt.Errorf("%w: BYWEEKNO", ErrNoCronEquivalent)
+	case len(r.byeaster) != 0:
+		return "", fmt.Errorf("%w: BYEASTER", ErrNoCronEquivalent)
+	case (len(r.bymonthday) != 0 || len(r.bynmonthday) != 0) && (len(r.byweekday) != 0 || len(r.bynweekday) != 0):
+		return "", fmt.Errorf("%w: BYMONTHDAY with BYDAY", ErrNoCronEquivalent)
+	case len(r.bynweekday) != 0 && r.freq == YEARLY && len(r.bymonth) == 0:
+		return "", fmt.Errorf("%w: BYDAY with an ordinal within a year", ErrNoCronEquivalent)
+	}
+
+	// field formats a time or month field whose unit may be the rule's
+	// frequency, in which case the interval has to divide the period.
+	field := func(freq Frequency, list []int, start, min, period int) (string, error) {
+		if r.freq != freq || r.interval == 1 {
+			if len(list) == 0 {
+				return "*", nil
+			}
+			return joinInts(list), nil
+		}
+		if period%r.interval != 0 {
+			return "", fmt.Errorf("%w: INTERVAL=%d with FREQ=%v", ErrNoCronEquivalent, r.interval, freq)
+		}
+		first := min + pymod(start-min, r.interval)
+		if len(list) == 0 {
+			return fmt.Sprintf("%d/%d", first, r.interval), nil
+		}
+		var values []int
+		for _, v := range list {
+			if pymod(v-first, r.interval) == 0 {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return "", fmt.Errorf("%w: rule has no occurrences", ErrNoCronEquivalent)
+		}
+		return joinInts(values), nil
+	}
+	if r.interval > 1 && (r.freq == YEARLY || r.freq == WEEKLY || r.freq == DAILY) {
+		return "", fmt.Errorf("%w: INTERVAL=%d with FREQ=%v", ErrNoCronEquivalent, r.interval, r.freq)
+	}
+
+	second, err := field(SECONDLY, sortedInts(r.bysecond), r.dtstart.Second(), 0, 60)
+	if err != nil {
+		return "", err
+	}
+	minute, err := field(MINUTELY, sortedInts(r.byminute), r.dtstart.Minute(), 0, 60)
+	if err != nil {
+		return "", err
+	}
+	hour, err := field(HOURLY, sortedInts(r.byhour), r.dtstart.Hour(), 0, 24)
+	if err != nil {
+		return "", err
+	}
+	month, err := field(MONTHLY, sortedInts(r.bymonth), int(r.dtstart.Month()), 1, 12)
+	if err != nil {
+		return "", err
+	}
+
+	dom := "*"
+	if len(r.bymonthday) != 0 || len(r.bynmonthday) != 0 {
+		var items []string
+		for _, v := range sortedInts(r.bymonthday) {
+			items = append(items, strconv.Itoa(v))
+		}
+		for _, v := range sortedInts(r.bynmonthday) {
+			if v == -1 {
+				items = append(items, "L")
+			} else {
+				items = append(items, fmt.Sprintf("L-%d", -v-1))
+			}
+		}
+		dom = strings.Join(items, ",")
+	}
+
+	dow := "*"
+	if len(r.byweekday) != 0 || len(r.bynweekday) != 0 {
+		var items []string
+		for _, v := range r.byweekday {
+			items = append(items, strconv.Itoa(toCronWeekday(Weekday{weekday: v})))
+		}
+		for _, wday := range r.bynweekday {
+			switch {
+			case wday.n == -1:
+				items = append(items, fmt.Sprintf("%dL", toCronWeekday(wday)))
+			case wday.n >= 1 && wday.n <= 5:
+				items = append(items, fmt.Sprintf("%d#%d", toCronWeekday(wday), wday.n))
+			default:
+				return "", fmt.Errorf("%w: BYDAY=%v", ErrNoCronEquivalent, wday)
+			}
+		}
+		dow = strings.Join(items, ",")
+	}
+
+	fields := []string{minute, hour, dom, month, dow}
+	if second != "0" {
+		fields = append([]string{second}, fields...)
+	}
+	return strings.Join(fields, " "), nil
+}
+
+func containsWeekday(list []Weekday, elem Weekday) bool {
+	for _, t := range list {
+		if t == elem {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedInts(list []int) []int {
+	result := append([]int(nil), list...)
+	sort.Ints(result)
+	return result
+}
+
+func joinInts(list []int) string {
+	items := make([]string, len(list))
+	for i, v := range list {
+		items[i] = strconv.Itoa(v)
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,128 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCronToROption(t *testing.T) {
+	dtstart := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		expr string
+		want string
+	}{
+		{"*/15 9-10 * * MON-FRI", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=9,10;BYMINUTE=0,15,30,45;BYSECOND=0"},
+		{"30 8 1,15 * *", "FREQ=DAILY;BYMONTHDAY=1,15;BYHOUR=8;BYMINUTE=30;BYSECOND=0"},
+		{"0 12 L * ?", "FREQ=DAILY;BYMONTHDAY=-1;BYHOUR=12;BYMINUTE=0;BYSECOND=0"},
+		{"0 12 L-2 JAN,JUL *", "FREQ=DAILY;BYMONTH=1,7;BYMONTHDAY=-3;BYHOUR=12;BYMINUTE=0;BYSECOND=0"},
+		{"0 9 ? * 2#2", "FREQ=MONTHLY;BYDAY=+2TU;BYHOUR=9;BYMINUTE=0;BYSECOND=0"},
+		{"0 9 ? * 5L", "FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=9;BYMINUTE=0;BYSECOND=0"},
+		{"0 9 LW * *", "FREQ=MONTHLY;BYSETPOS=-1;BYDAY=MO,TU,WE,TH,FR;BYHOUR=9;BYMINUTE=0;BYSECOND=0"},
+		{"*/10 * * * * *", "FREQ=MINUTELY;BYSECOND=0,10,20,30,40,50"},
+		{"@weekly", "FREQ=DAILY;BYDAY=SU;BYHOUR=0;BYMINUTE=0;BYSECOND=0"},
+	}
+	for _, c := range cases {
+		option, err := CronToROption(c.expr, dtstart)
+		if err != nil {
+			t.Errorf("CronToROption(%q) returned error: %v", c.expr, err)
+			continue
+		}
+		if value := option.RRuleString(); value != c.want {
+			t.Errorf("CronToROption(%q) = %v, want %v", c.expr, value, c.want)
+		}
+	}
+}
+
+func TestCronToRRule(t *testing.T) {
+	r, err := CronToRRule("0 9 1W * *", time.Date(1997, 11, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CronToRRule returned error: %v", err)
+	}
+	value := r.Between(time.Date(1997, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(1998, 2, 1, 0, 0, 0, 0, time.UTC), true)
+	want := []time.Time{
+		time.Date(1997, 11, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 12, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(1998, 1, 1, 9, 0, 0, 0, time.UTC),
+	}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestCronToROptionErrors(t *testing.T) {
+	cases := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * * MON#6",
+		"0 9 1 * MON",
+		"0 9 15W * *",
+	}
+	for _, expr := range cases {
+		if _, err := CronToROption(expr, time.Time{}); err == nil {
+			t.Errorf("CronToROption(%q) = nil, want error", expr)
+		}
+	}
+	if _, err := CronToROption("0 9 15W * *", time.Time{}); !errors.Is(err, ErrNoCronEquivalent) {
+		t.Errorf("get %v, want ErrNoCronEquivalent", err)
+	}
+}
+
+func TestCronString(t *testing.T) {
+	dtstart := time.Date(1997, 9, 2, 9, 30, 0, 0, time.UTC)
+	cases := []struct {
+		option ROption
+		want   string
+	}{
+		{ROption{Freq: DAILY}, "30 9 * * *"},
+		{ROption{Freq: WEEKLY}, "30 9 * * 2"},
+		{ROption{Freq: WEEKLY, Byweekday: []Weekday{MO, SU}}, "30 9 * * 1,0"},
+		{ROption{Freq: MONTHLY}, "30 9 2 * *"},
+		{ROption{Freq: MONTHLY, Interval: 3}, "30 9 2 3/3 *"},
+		{ROption{Freq: MONTHLY, Bymonthday: []int{1, -1}}, "30 9 1,L * *"},
+		{ROption{Freq: MONTHLY, Byweekday: []Weekday{FR.Nth(-1), MO.Nth(1)}}, "30 9 * * 5L,1#1"},
+		{ROption{Freq: YEARLY}, "30 9 2 9 *"},
+		{ROption{Freq: HOURLY, Interval: 6}, "30 3/6 * * *"},
+		{ROption{Freq: MINUTELY, Interval: 20, Byhour: []int{9, 10}}, "10/20 9,10 * * *"},
+		{ROption{Freq: SECONDLY, Bysecond: []int{15}}, "15 * * * * *"},
+	}
+	for _, c := range cases {
+		c.option.Dtstart = dtstart
+		value, err := c.option.CronString()
+		if err != nil {
+			t.Errorf("CronString(%v) returned error: %v", c.option.RRuleString(), err)
+			continue
+		}
+		if value != c.want {
+			t.Errorf("CronString(%v) = %v, want %v", c.option.RRuleString(), value, c.want)
+		}
+	}
+}
+
+func TestCronStringErrors(t *testing.T) {
+	dtstart := time.Date(1997, 9, 2, 9, 30, 0, 0, time.UTC)
+	cases := []ROption{
+		{Freq: WEEKLY, Interval: 3},
+		{Freq: DAILY, Interval: 2},
+		{Freq: HOURLY, Interval: 5},
+		{Freq: MONTHLY, Bysetpos: []int{1}, Byweekday: []Weekday{MO, TU}},
+		{Freq: DAILY, Count: 3},
+		{Freq: DAILY, Until: dtstart.AddDate(1, 0, 0)},
+		{Freq: YEARLY, Byweekno: []int{1}},
+		{Freq: MONTHLY, Bymonthday: []int{1}, Byweekday: []Weekday{MO}},
+		{Freq: YEARLY, Byweekday: []Weekday{MO.Nth(20)}},
+	}
+	for _, option := range cases {
+		option.Dtstart = dtstart
+		if _, err := option.CronString(); !errors.Is(err, ErrNoCronEquivalent) {
+			t.Errorf("CronString(%v) = %v, want ErrNoCronEquivalent", option.RRuleString(), err)
+		}
+	}
+}
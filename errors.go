@@ -0,0 +1,168 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kinds of ParseError, usable with errors.Is.
+var (
+	// ErrSyntax reports malformed input, such as a rule part without "=".
+	ErrSyntax = errors.New("wrong format")
+	// ErrUnknownProperty reports an unknown property or rule part.
+	ErrUnknownProperty = errors.New("unknown property")
+	// ErrMissingValue reports a rule part or property without value.
+	ErrMissingValue = errors.New("missing value")
+	// ErrMissingProperty reports a required property or rule part that is
+	// absent, such as FREQ.
+	ErrMissingProperty = errors.New("missing property")
+	// ErrInvalidValue reports a value that cannot be parsed, such as an
+	// unknown frequency or a malformed date.
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrOutOfRange reports a value outside the bounds defined in RFC 5545.
+	ErrOutOfRange = errors.New("value out of range")
+	// ErrNotAllowed reports a rule part or combination of rule parts that
+	// RFC 5545 forbids but this package accepts by default.
+	ErrNotAllowed = errors.New("not allowed by RFC 5545")
+	// ErrLimitExceeded reports a query stopped by its Limits or context.
+	ErrLimitExceeded = errors.New("iteration limit exceeded")
+)
+
+// ParseError describes an invalid RRULE, DTSTART, RDATE or EXDATE, or an
+// ROption rejected by NewRRule. It locates the offending token for editors.
+type ParseError struct {
+	// Line is the 1-based line number of the property, or 0 if unknown.
+	Line int
+	// Property is the property name, e.g. "RRULE" or "DTSTART".
+	Property string
+	// Param is the rule part or property parameter, e.g. "BYDAY" or "TZID".
+	Param string
+	// Offset is the byte offset of Value within the line.
+	Offset int
+	// Value is the offending token.
+	Value string
+	// Kind is one of the Err* kinds of this package.
+	Kind error
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	var location []string
+	if e.Line > 0 {
+		location = append(location, fmt.Sprintf("line %d", e.Line))
+	}
+	if e.Property != "" {
+		location = append(location, e.Property)
+	}
+
+	msg := e.Kind.Error()
+	if e.Err != nil {
+		msg = e.Err.Error()
+	} else {
+		if e.Param != "" {
+			msg = e.Param + " " + msg
+		}
+		if e.Value != "" {
+			msg = fmt.Sprintf("%s: %s", msg, e.Value)
+		}
+	}
+	if len(location) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(location, " "), msg)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of e.
+func (e *ParseError) Is(target error) bool {
+	return e.Kind == target
+}
+
+// ValidationError lists every violation found by a validation that does not
+// stop at the first one, such as ValidateRFC5545.
+type ValidationError struct {
+	Errors []*ParseError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the violations is target or of kind target.
+func (e *ValidationError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// locateError attributes err to the token value found at offset in the given
+// line and property. A *ParseError keeps its own Param and Value and has its
+// Offset shifted by offset; other errors are wrapped with kind. The
+// violations of a *ValidationError are located one by one.
+func locateError(err error, kind error, line int, property, param string, offset int, value string) error {
+	if v, ok := err.(*ValidationError); ok {
+		for _, pe := range v.Errors {
+			locateError(pe, kind, line, property, param, offset, value)
+		}
+		return v
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		return &ParseError{Line: line, Property: property, Param: param,
+			Offset: offset, Value: value, Kind: kind, Err: err}
+	}
+	if line > 0 {
+		pe.Line = line
+	}
+	if property != "" {
+		pe.Property = property
+	}
+	if pe.Param == "" {
+		pe.Param = param
+	}
+	if pe.Value == "" {
+		pe.Value = value
+	}
+	pe.Offset += offset
+	return pe
+}
+
+// LimitError reports a query stopped before its end by one of its Limits or
+// by its context. It matches ErrLimitExceeded with errors.Is, and unwraps to
+// the error of the context if the context stopped it.
+type LimitError struct {
+	// Limit is the exceeded limit: "occurrences", "iterations" or "context".
+	Limit string
+	// Err is the error of the context, if any.
+	Err error
+}
+
+func (e *LimitError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s", ErrLimitExceeded, e.Err)
+	}
+	return fmt.Sprintf("%s: too many %s", ErrLimitExceeded, e.Limit)
+}
+
+// Unwrap returns the error of the context, if any.
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrLimitExceeded.
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
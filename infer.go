@@ -0,0 +1,219 @@
+package rrule
+
+import (
+	"errors"
+	"time"
+)
+
+// Infer returns the simplest ROption that reproduces the given dates, along
+// with a Set made of that rule plus the RDATE and EXDATE residue needed to
+// reproduce exactly those dates.
+//
+// The dates must be sorted in ascending order; duplicates are ignored.
+// Candidate rules start at the first date and are checked by expanding them
+// with this package. The candidate needing the fewest RDATEs and EXDATEs
+// wins, ties going to the rule with the fewest parts. The returned ROption
+// carries a COUNT rather than an UNTIL.
+func Infer(dates []time.Time) (ROption, *Set, error) {
+	if len(dates) == 0 {
+		return ROption{}, nil, errors.New("no dates to infer from")
+	}
+	uniq := make([]time.Time, 0, len(dates))
+	for i, dt := range dates {
+		dt = dt.Truncate(time.Second)
+		if i > 0 {
+			last := uniq[len(uniq)-1]
+			if dt.Before(last) {
+				return ROption{}, nil, errors.New("dates must be sorted")
+			}
+			if dt.Equal(last) {
+				continue
+			}
+		}
+		uniq = append(uniq, dt)
+	}
+
+	var best ROption
+	var bestResidue, bestCount int
+	bestParts := -1
+	for _, option := range inferCandidates(uniq) {
+		parts := optionParts(option)
+		limit := len(uniq)
+		if bestParts >= 0 {
+			limit = bestResidue
+			if parts < bestParts {
+				// A simpler rule also wins on equal residue.
+				limit++
+			}
+		}
+		residue, count, ok := inferResidue(option, uniq, limit)
+		if !ok {
+			continue
+		}
+		best, bestResidue, bestCount, bestParts = option, residue, count, parts
+	}
+
+	if bestParts < 0 {
+		// Nothing beats listing the dates one by one.
+		best, bestCount = ROption{Freq: YEARLY, Dtstart: uniq[0]}, 1
+	}
+	best.Until = time.Time{}
+	best.Count = bestCount
+	r, err := NewRRule(best)
+	if err != nil {
+		return ROption{}, nil, err
+	}
+	set := &Set{}
+	set.RRule(r)
+	next := r.Iterator()
+	generated, ok := next()
+	for _, dt := range uniq {
+		for ok && generated.Before(dt) {
+			set.ExDate(generated)
+			generated, ok = next()
+		}
+		if ok && generated.Equal(dt) {
+			generated, ok = next()
+		} else {
+			set.RDate(dt)
+		}
+	}
+	for ok {
+		set.ExDate(generated)
+		generated, ok = next()
+	}
+	return best, set, nil
+}
+
+// inferResidue expands option up to the last date and returns how many
+// RDATEs and EXDATEs are needed to turn it into dates, along with the number
+// of occurrences generated. It gives up once the residue reaches limit.
+func inferResidue(option ROption, dates []time.Time, limit int) (residue, count int, ok bool) {
+	option.Until = dates[len(dates)-1]
+	r, err := NewRRule(option)
+	if err != nil {
+		return 0, 0, false
+	}
+	next := r.Iterator()
+	generated, more := next()
+	for _, dt := range dates {
+		for more && generated.Before(dt) {
+			residue++
+			count++
+			if residue >= limit {
+				return 0, 0, false
+			}
+			generated, more = next()
+		}
+		if more && generated.Equal(dt) {
+			count++
+			generated, more = next()
+			continue
+		}
+		residue++
+		if residue >= limit {
+			return 0, 0, false
+		}
+	}
+	return residue, count, count != 0 && residue < limit
+}
+
+// optionParts returns the number of rule parts besides FREQ and DTSTART.
+func optionParts(option ROption) int {
+	parts := 0
+	if option.Interval > 1 {
+		parts++
+	}
+	for _, l := range []int{
+		len(option.Bysetpos), len(option.Bymonth), len(option.Bymonthday),
+		len(option.Byyearday), len(option.Byweekno), len(option.Byweekday),
+		len(option.Byhour), len(option.Byminute), len(option.Bysecond),
+		len(option.Byeaster),
+	} {
+		if l != 0 {
+			parts++
+		}
+	}
+	return parts
+}
+
+// inferCandidates returns the rules worth checking against dates, simplest
+// first.
+func inferCandidates(dates []time.Time) []ROption {
+	first := dates[0]
+	year, month, day := first.Date()
+	weekday := Weekday{weekday: toPyWeekday(first.Weekday())}
+	nth := (day-1)/7 + 1
+	lastWeek := day+7 > daysIn(month, year)
+	lastDay := day == daysIn(month, year)
+
+	// Intervals suggested by the gap between the first two dates.
+	intervals := map[Frequency]int{}
+	if len(dates) > 1 {
+		second := dates[1]
+		gap := second.Sub(first)
+		if gap%time.Hour == 0 {
+			intervals[HOURLY] = int(gap / time.Hour)
+		}
+		if gap%time.Minute == 0 {
+			intervals[MINUTELY] = int(gap / time.Minute)
+		}
+		intervals[SECONDLY] = int(gap / time.Second)
+		y2, m2, d2 := second.Date()
+		days := int(time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC).Sub(time.Date(year, month, day, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+		intervals[DAILY] = days
+		intervals[WEEKLY] = days / 7
+		intervals[MONTHLY] = (y2-year)*12 + int(m2-month)
+		intervals[YEARLY] = y2 - year
+	}
+	interval := func(freq Frequency) []int {
+		if n := intervals[freq]; n > 1 {
+			return []int{0, n}
+		}
+		return []int{0}
+	}
+
+	// Weekdays seen across all dates, in week order.
+	seen := make([]bool, 7)
+	for _, dt := range dates {
+		seen[toPyWeekday(dt.Weekday())] = true
+	}
+	var weekdays []Weekday
+	for i, ok := range seen {
+		if ok {
+			weekdays = append(weekdays, Weekday{weekday: i})
+		}
+	}
+
+	var result []ROption
+	add := func(option ROption) {
+		option.Dtstart = first
+		result = append(result, option)
+	}
+	for _, freq := range []Frequency{YEARLY, MONTHLY, WEEKLY, DAILY, HOURLY, MINUTELY, SECONDLY} {
+		for _, n := range interval(freq) {
+			add(ROption{Freq: freq, Interval: n})
+		}
+	}
+	for _, n := range interval(WEEKLY) {
+		if len(weekdays) > 1 {
+			add(ROption{Freq: WEEKLY, Interval: n, Byweekday: weekdays})
+		}
+	}
+	for _, n := range interval(YEARLY) {
+		add(ROption{Freq: YEARLY, Interval: n, Bymonth: []int{int(month)}, Byweekday: []Weekday{weekday.Nth(nth)}})
+		if lastWeek {
+			add(ROption{Freq: YEARLY, Interval: n, Bymonth: []int{int(month)}, Byweekday: []Weekday{weekday.Nth(-1)}})
+		}
+	}
+	for _, n := range interval(MONTHLY) {
+		add(ROption{Freq: MONTHLY, Interval: n, Byweekday: []Weekday{weekday.Nth(nth)}})
+		if lastWeek {
+			add(ROption{Freq: MONTHLY, Interval: n, Byweekday: []Weekday{weekday.Nth(-1)}})
+		}
+		if lastDay {
+			add(ROption{Freq: MONTHLY, Interval: n, Bymonthday: []int{-1}})
+		}
+	}
+	return result
+}
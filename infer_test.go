@@ -0,0 +1,71 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInfer(t *testing.T) {
+	cases := []struct {
+		option ROption
+		want   string
+	}{
+		{ROption{Freq: DAILY, Count: 10}, "FREQ=DAILY;COUNT=10"},
+		{ROption{Freq: WEEKLY, Interval: 2, Count: 6}, "FREQ=WEEKLY;INTERVAL=2;COUNT=6"},
+		{ROption{Freq: WEEKLY, Count: 9, Byweekday: []Weekday{TU, TH}}, "FREQ=WEEKLY;COUNT=9;BYDAY=TU,TH"},
+		{ROption{Freq: MONTHLY, Count: 12, Byweekday: []Weekday{TU.Nth(2)}}, "FREQ=MONTHLY;COUNT=12;BYDAY=+2TU"},
+		{ROption{Freq: MONTHLY, Count: 12, Bymonthday: []int{-1}}, "FREQ=MONTHLY;COUNT=12;BYMONTHDAY=-1"},
+		{ROption{Freq: YEARLY, Count: 6, Bymonth: []int{11}, Byweekday: []Weekday{TH.Nth(4)}}, "FREQ=YEARLY;COUNT=6;BYMONTH=11;BYDAY=+4TH"},
+		{ROption{Freq: DAILY, Count: 20, Byweekday: []Weekday{MO, TU, WE, TH, FR}}, "FREQ=WEEKLY;COUNT=20;BYDAY=MO,TU,WE,TH,FR"},
+	}
+	for _, c := range cases {
+		c.option.Dtstart = time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC)
+		r, _ := NewRRule(c.option)
+		dates := r.All()
+		option, set, err := Infer(dates)
+		if err != nil {
+			t.Errorf("Infer(%v) returned error: %v", c.want, err)
+			continue
+		}
+		if value := option.RRuleString(); value != c.want {
+			t.Errorf("get %v, want %v", value, c.want)
+		}
+		if value := set.All(); !timesEqual(value, dates) {
+			t.Errorf("get %v, want %v", value, dates)
+		}
+	}
+}
+
+func TestInferResidue(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: WEEKLY, Count: 10,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	dates := r.All()
+	extra := time.Date(1997, 9, 20, 9, 0, 0, 0, time.UTC)
+	dates = append(dates[:3], append([]time.Time{extra}, dates[4:]...)...)
+	option, set, err := Infer(dates)
+	if err != nil {
+		t.Fatalf("Infer returned error: %v", err)
+	}
+	if want := "FREQ=WEEKLY;COUNT=10"; option.RRuleString() != want {
+		t.Errorf("get %v, want %v", option.RRuleString(), want)
+	}
+	if value := set.GetRDate(); len(value) != 1 || value[0] != extra {
+		t.Errorf("get %v, want [%v]", value, extra)
+	}
+	if value := set.GetExDate(); len(value) != 1 || value[0] != time.Date(1997, 9, 23, 9, 0, 0, 0, time.UTC) {
+		t.Errorf("get %v, want [1997-09-23 09:00:00]", value)
+	}
+	if value := set.All(); !timesEqual(value, dates) {
+		t.Errorf("get %v, want %v", value, dates)
+	}
+}
+
+func TestInferErrors(t *testing.T) {
+	if _, _, err := Infer(nil); err == nil {
+		t.Error("get nil, want error")
+	}
+	dates := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC), time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC)}
+	if _, _, err := Infer(dates); err == nil {
+		t.Error("get nil, want error")
+	}
+}
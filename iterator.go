@@ -0,0 +1,75 @@
+package rrule
+
+import "time"
+
+// RRuleIterator iterates over the occurrences of an RRule. Unlike the
+// function returned by RRule.Iterator, it can be Reset onto another rule
+// while keeping its buffers, so that expanding many rules allocates nothing
+// per occurrence once the buffers have grown.
+type RRuleIterator struct {
+	iterator rIterator
+	// peek holds the occurrence that ended the last AppendBetween, if
+	// peeked is true, so that the next call starts with it.
+	peek   time.Time
+	peeked bool
+}
+
+// NewRRuleIterator returns an iterator over the occurrences of r.
+func NewRRuleIterator(r *RRule) *RRuleIterator {
+	it := &RRuleIterator{}
+	it.Reset(r)
+	return it
+}
+
+// Reset restarts the iterator at the first occurrence of r. The cache of r,
+// if any, is not used.
+func (it *RRuleIterator) Reset(r *RRule) {
+	it.iterator.reset(r)
+	it.peeked = false
+}
+
+// Next returns the next occurrence, or false if there is none.
+func (it *RRuleIterator) Next() (time.Time, bool) {
+	if it.peeked {
+		it.peeked = false
+		return it.peek, true
+	}
+	return it.iterator.next()
+}
+
+// AppendBetween appends to dst the remaining occurrences between after and
+// before and returns the extended slice. The inc keyword has the same
+// meaning as in RRule.Between. The first occurrence past before is kept for
+// the next call, so that consecutive windows miss no occurrence.
+func (it *RRuleIterator) AppendBetween(dst []time.Time, after, before time.Time, inc bool) []time.Time {
+	for {
+		v, ok := it.Next()
+		if !ok {
+			return dst
+		}
+		if inc && v.After(before) || !inc && !v.Before(before) {
+			it.peek, it.peeked = v, true
+			return dst
+		}
+		if inc && !v.Before(after) || !inc && v.After(after) {
+			dst = append(dst, v)
+		}
+	}
+}
+
+// AppendBetween is same as Between, but appends the occurrences to dst and
+// returns the extended slice, so that dst can be reused.
+func (r *RRule) AppendBetween(dst []time.Time, after, before time.Time, inc bool) []time.Time {
+	if r.cache != nil {
+		return appendBetween(dst, r.cache.iterator(), after, before, inc)
+	}
+	var it RRuleIterator
+	it.Reset(r)
+	return it.AppendBetween(dst, after, before, inc)
+}
+
+// AppendBetween is same as Between, but appends the occurrences to dst and
+// returns the extended slice, so that dst can be reused.
+func (set *Set) AppendBetween(dst []time.Time, after, before time.Time, inc bool) []time.Time {
+	return appendBetween(dst, set.Iterator(), after, before, inc)
+}
@@ -0,0 +1,104 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRRuleIterator(t *testing.T) {
+	dtstart := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	rules := []ROption{
+		{Freq: DAILY, Dtstart: dtstart},
+		{Freq: HOURLY, Dtstart: dtstart, Byminute: []int{0, 30}},
+		{Freq: MONTHLY, Dtstart: dtstart, Byweekday: []Weekday{MO.Nth(1), FR.Nth(-1)}},
+		{Freq: YEARLY, Dtstart: dtstart, Byweekno: []int{1, 20}, Byweekday: []Weekday{MO}},
+		{Freq: YEARLY, Dtstart: dtstart, Byeaster: []int{0}},
+	}
+	after, before := dtstart.AddDate(0, 1, 0), dtstart.AddDate(3, 0, 0)
+	it := &RRuleIterator{}
+	var dst []time.Time
+	for _, option := range append(rules, rules...) {
+		r, err := NewRRule(option)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := r.Between(after, before, true)
+		it.Reset(r)
+		dst = it.AppendBetween(dst[:0], after, before, true)
+		if !timesEqual(dst, want) {
+			t.Errorf("%v: get %v, want %v", r, dst, want)
+		}
+		if got := r.AppendBetween([]time.Time{dtstart}, after, before, true); !timesEqual(got[1:], want) || !got[0].Equal(dtstart) {
+			t.Errorf("%v: get %v, want %v after DTSTART", r, got, want)
+		}
+	}
+}
+
+func TestRRuleIteratorWindows(t *testing.T) {
+	dtstart := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	r, _ := NewRRule(ROption{Freq: DAILY, Dtstart: dtstart})
+	it := NewRRuleIterator(r)
+	first := it.AppendBetween(nil, dtstart, dtstart.AddDate(0, 0, 2), true)
+	second := it.AppendBetween(nil, dtstart.AddDate(0, 0, 2), dtstart.AddDate(0, 0, 5), false)
+	want := []time.Time{dtstart.AddDate(0, 0, 3), dtstart.AddDate(0, 0, 4)}
+	if len(first) != 3 || !timesEqual(second, want) {
+		t.Errorf("get %v and %v, want 3 occurrences and %v", first, second, want)
+	}
+	if value, _ := it.Next(); !value.Equal(dtstart.AddDate(0, 0, 5)) {
+		t.Errorf("get %v, want %v", value, dtstart.AddDate(0, 0, 5))
+	}
+}
+
+func TestRRuleIteratorAllocs(t *testing.T) {
+	dtstart := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	var rrules []*RRule
+	for _, option := range []ROption{
+		{Freq: DAILY, Dtstart: dtstart},
+		{Freq: HOURLY, Dtstart: dtstart, Byminute: []int{0, 30}},
+		{Freq: MONTHLY, Dtstart: dtstart, Byweekday: []Weekday{MO.Nth(1), FR.Nth(-1)}},
+		{Freq: YEARLY, Dtstart: dtstart, Byweekno: []int{1, 20}, Byweekday: []Weekday{MO}},
+	} {
+		r, err := NewRRule(option)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rrules = append(rrules, r)
+	}
+	after, before := dtstart, dtstart.AddDate(2, 0, 0)
+	it := &RRuleIterator{}
+	dst := make([]time.Time, 0, 100000)
+	expand := func() {
+		for _, r := range rrules {
+			it.Reset(r)
+			dst = it.AppendBetween(dst[:0], after, before, true)
+		}
+	}
+	expand()
+	if allocs := testing.AllocsPerRun(10, expand); allocs != 0 {
+		t.Errorf("get %v allocations, want 0", allocs)
+	}
+}
+
+func BenchmarkAppendBetween(b *testing.B) {
+	dtstart := time.Date(2000, 03, 22, 12, 0, 0, 0, time.UTC)
+	r, err := NewRRule(ROption{Dtstart: dtstart, Freq: HOURLY})
+	if err != nil {
+		b.Fatalf("failed to init rrule: %s", err)
+	}
+	after, before := dtstart, dtstart.AddDate(0, 0, 90)
+	b.Run("Between", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r.Between(after, before, true)
+		}
+	})
+	b.Run("RRuleIterator", func(b *testing.B) {
+		b.ReportAllocs()
+		it := NewRRuleIterator(r)
+		var dst []time.Time
+		for i := 0; i < b.N; i++ {
+			it.Reset(r)
+			dst = it.AppendBetween(dst[:0], after, before, true)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package rrule
+
+import (
+	"context"
+	"time"
+)
+
+// Limits bounds the work of a query, so that rules without COUNT or UNTIL,
+// or rules that never match, cannot make it run for long. Zero values mean
+// no limit. A stopped query returns what it found so far with the error.
+type Limits struct {
+	// MaxOccurrences is the number of occurrences a query may go through,
+	// including those it skips before the queried window.
+	MaxOccurrences int
+	// MaxIterations is the number of periods of the rule's frequency a
+	// query may go through, whether they contain occurrences or not.
+	MaxIterations int
+}
+
+// contextCheckInterval is the number of periods between two checks of the
+// context, which are comparatively slow.
+const contextCheckInterval = 256
+
+// limiter enforces Limits and a context over one query. It records the
+// *LimitError that stopped the query, if any.
+type limiter struct {
+	ctx         context.Context
+	limits      Limits
+	iterations  int
+	occurrences int
+	err         error
+}
+
+func newLimiter(ctx context.Context, limits Limits) *limiter {
+	l := &limiter{ctx: ctx, limits: limits}
+	l.checkContext()
+	return l
+}
+
+func (l *limiter) checkContext() bool {
+	if l.err == nil {
+		if err := l.ctx.Err(); err != nil {
+			l.err = &LimitError{Limit: "context", Err: err}
+		}
+	}
+	return l.err == nil
+}
+
+// iterate counts a period of the rule and reports whether the query may go
+// on.
+func (l *limiter) iterate() bool {
+	if l.err != nil {
+		return false
+	}
+	l.iterations++
+	if l.limits.MaxIterations > 0 && l.iterations > l.limits.MaxIterations {
+		l.err = &LimitError{Limit: "iterations"}
+		return false
+	}
+	return l.iterations%contextCheckInterval != 0 || l.checkContext()
+}
+
+// wrap returns the occurrences of next, counting them, until a limit is
+// exceeded.
+func (l *limiter) wrap(next Next) Next {
+	return func() (time.Time, bool) {
+		if l.err != nil {
+			return time.Time{}, false
+		}
+		dt, ok := next()
+		if !ok {
+			return dt, false
+		}
+		l.occurrences++
+		if l.limits.MaxOccurrences > 0 && l.occurrences > l.limits.MaxOccurrences {
+			l.err = &LimitError{Limit: "occurrences"}
+			return time.Time{}, false
+		}
+		return dt, true
+	}
+}
+
+func (r *RRule) limitedIterator(l *limiter) Next {
+	iterator := &rIterator{}
+	iterator.reset(r)
+	iterator.limiter = l
+	return l.wrap(iterator.next)
+}
+
+func (set *Set) limitedIterator(l *limiter) Next {
+	if set.rrule == nil {
+		return l.wrap(set.merge(nil))
+	}
+	iterator := &rIterator{}
+	iterator.reset(set.rrule)
+	iterator.limiter = l
+	return l.wrap(set.merge(iterator.next))
+}
+
+// AllContext is same as All, but stops with a *LimitError when limits are
+// exceeded or ctx is done. It does not use the cache of the rule.
+func (r *RRule) AllContext(ctx context.Context, limits Limits) ([]time.Time, error) {
+	l := newLimiter(ctx, limits)
+	result := all(r.limitedIterator(l))
+	return result, l.err
+}
+
+// BetweenContext is same as Between, but stops with a *LimitError when
+// limits are exceeded or ctx is done.
+func (r *RRule) BetweenContext(ctx context.Context, limits Limits, after, before time.Time, inc bool) ([]time.Time, error) {
+	l := newLimiter(ctx, limits)
+	result := between(r.limitedIterator(l), after, before, inc)
+	return result, l.err
+}
+
+// BeforeContext is same as Before, but stops with a *LimitError when limits
+// are exceeded or ctx is done.
+func (r *RRule) BeforeContext(ctx context.Context, limits Limits, dt time.Time, inc bool) (time.Time, error) {
+	l := newLimiter(ctx, limits)
+	result := before(r.limitedIterator(l), dt, inc)
+	return result, l.err
+}
+
+// AfterContext is same as After, but stops with a *LimitError when limits
+// are exceeded or ctx is done.
+func (r *RRule) AfterContext(ctx context.Context, limits Limits, dt time.Time, inc bool) (time.Time, error) {
+	l := newLimiter(ctx, limits)
+	result := after(r.limitedIterator(l), dt, inc)
+	return result, l.err
+}
+
+// AllContext is same as All, but stops with a *LimitError when limits are
+// exceeded or ctx is done. It does not use the cache of the set.
+func (set *Set) AllContext(ctx context.Context, limits Limits) ([]time.Time, error) {
+	l := newLimiter(ctx, limits)
+	result := all(set.limitedIterator(l))
+	return result, l.err
+}
+
+// BetweenContext is same as Between, but stops with a *LimitError when
+// limits are exceeded or ctx is done.
+func (set *Set) BetweenContext(ctx context.Context, limits Limits, after, before time.Time, inc bool) ([]time.Time, error) {
+	l := newLimiter(ctx, limits)
+	result := between(set.limitedIterator(l), after, before, inc)
+	return result, l.err
+}
+
+// BeforeContext is same as Before, but stops with a *LimitError when limits
+// are exceeded or ctx is done.
+func (set *Set) BeforeContext(ctx context.Context, limits Limits, dt time.Time, inc bool) (time.Time, error) {
+	l := newLimiter(ctx, limits)
+	result := before(set.limitedIterator(l), dt, inc)
+	return result, l.err
+}
+
+// AfterContext is same as After, but stops with a *LimitError when limits
+// are exceeded or ctx is done.
+func (set *Set) AfterContext(ctx context.Context, limits Limits, dt time.Time, inc bool) (time.Time, error) {
+	l := newLimiter(ctx, limits)
+	result := after(set.limitedIterator(l), dt, inc)
+	return result, l.err
+}
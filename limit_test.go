@@ -0,0 +1,76 @@
+package rrule
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimitOccurrences(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY, Dtstart: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)})
+	value, err := r.AllContext(context.Background(), Limits{MaxOccurrences: 10})
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("get %v, want %v", err, ErrLimitExceeded)
+	}
+	if len(value) != 10 {
+		t.Errorf("get %d occurrences, want 10", len(value))
+	}
+
+	value, err = r.BetweenContext(context.Background(), Limits{MaxOccurrences: 10},
+		time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC), true)
+	if err != nil || len(value) != 3 {
+		t.Errorf("get %v, %v, want 3 occurrences", value, err)
+	}
+}
+
+func TestLimitIterations(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: YEARLY, Bymonth: []int{2}, Bymonthday: []int{30},
+		Dtstart: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)})
+	_, err := r.AfterContext(context.Background(), Limits{MaxIterations: 100}, r.GetDTStart(), true)
+	var le *LimitError
+	if !errors.As(err, &le) || le.Limit != "iterations" {
+		t.Errorf("get %v, want too many iterations", err)
+	}
+
+	set := &Set{}
+	set.RRule(r)
+	set.RDate(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+	value, err := set.AllContext(context.Background(), Limits{MaxIterations: 100})
+	// The RDATE is not returned, as the rule might have occurred before it.
+	if !errors.Is(err, ErrLimitExceeded) || len(value) != 0 {
+		t.Errorf("get %v, %v, want no occurrence and %v", value, err, ErrLimitExceeded)
+	}
+}
+
+func TestLimitContext(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: SECONDLY, Dtstart: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := r.AllContext(ctx, Limits{})
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("get %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	set := &Set{}
+	set.RDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	if _, err := set.BeforeContext(ctx, Limits{}, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), true); !errors.Is(err, context.Canceled) {
+		t.Errorf("get %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestLimitUnreachableHour(t *testing.T) {
+	// Hours are even from DTSTART on, so BYHOUR=9 is never reached.
+	r, _ := NewRRule(ROption{Freq: HOURLY, Interval: 2, Byhour: []int{9},
+		Dtstart: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := r.AfterContext(ctx, Limits{}, r.GetDTStart(), true); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("get %v, want %v", err, context.DeadlineExceeded)
+	}
+	if _, err := r.AfterContext(context.Background(), Limits{MaxIterations: 1000}, r.GetDTStart(), true); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("get %v, want %v", err, ErrLimitExceeded)
+	}
+}
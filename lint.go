@@ -0,0 +1,285 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LintKind classifies the issues reported by Lint.
+type LintKind int
+
+// Lint issue kinds
+const (
+	// LintEmpty reports a rule or set without any occurrence.
+	LintEmpty LintKind = iota
+	// LintDisallowed reports a combination of rule parts that RFC 5545
+	// forbids.
+	LintDisallowed
+	// LintNeverMatches reports a BY* value that can never select an
+	// occurrence.
+	LintNeverMatches
+	// LintIgnored reports a rule part or date that has no effect.
+	LintIgnored
+)
+
+func (k LintKind) String() string {
+	return [...]string{"empty", "disallowed", "never matches", "ignored"}[k]
+}
+
+// LintIssue is a problem found by Lint.
+type LintIssue struct {
+	Kind LintKind
+	// Param is the rule part or property concerned, e.g. "BYMONTHDAY", or
+	// empty if the issue concerns the whole rule.
+	Param   string
+	Message string
+}
+
+func (i LintIssue) String() string {
+	if i.Param == "" {
+		return fmt.Sprintf("%s: %s", i.Kind, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Kind, i.Param, i.Message)
+}
+
+// lintYears is the length of the Gregorian cycle, after which weekdays and
+// leap years repeat.
+const lintYears = 400
+
+// Lint statically analyzes option and reports rules without occurrences,
+// combinations forbidden by RFC 5545, BY* values that can never match and
+// parts without effect. It does not iterate over the occurrences.
+func Lint(option ROption) []LintIssue {
+	issues := []LintIssue{}
+	if err := validateBounds(option); err != nil {
+		var pe *ParseError
+		errors.As(err, &pe)
+		return append(issues, LintIssue{LintNeverMatches, pe.Param, err.Error()})
+	}
+	issues = append(issues, lintDisallowed(option)...)
+	issues = append(issues, lintIgnored(option)...)
+
+	empty := false
+	if !option.Until.IsZero() && !option.Dtstart.IsZero() && option.Until.Before(option.Dtstart) {
+		issues = append(issues, LintIssue{LintEmpty, "UNTIL", "UNTIL is before DTSTART"})
+		empty = true
+	}
+	for _, part := range lintValues(option) {
+		var never int
+		for _, v := range part.values {
+			if !part.match(v) {
+				issues = append(issues, LintIssue{LintNeverMatches, part.param,
+					fmt.Sprintf("%s=%s %s", part.param, part.format(v), part.reason)})
+				never++
+			}
+		}
+		if never != 0 && never == len(part.values) {
+			issues = append(issues, LintIssue{LintEmpty, part.param,
+				fmt.Sprintf("no value of %s can match", part.param)})
+			empty = true
+		}
+	}
+	if !empty && !anyDayMatches(option) {
+		issues = append(issues, LintIssue{LintEmpty, "", "no day matches all the BY* parts"})
+	}
+	return issues
+}
+
+func lintDisallowed(option ROption) []LintIssue {
+	var issues []LintIssue
+	errs := rfcViolations(option)
+	if err := untilViolation(option); err != nil {
+		errs = append(errs, err)
+	}
+	for _, err := range errs {
+		issues = append(issues, LintIssue{LintDisallowed, err.Param, err.Err.Error()})
+	}
+	return issues
+}
+
+func lintIgnored(option ROption) []LintIssue {
+	var issues []LintIssue
+	if option.Freq > MONTHLY {
+		for _, w := range option.Byweekday {
+			if w.n != 0 {
+				issues = append(issues, LintIssue{LintIgnored, "BYDAY",
+					fmt.Sprintf("BYDAY=%s: the number is ignored with FREQ=%s", w, option.Freq)})
+			}
+		}
+	}
+	if option.Wkst != MO && len(option.Byweekno) == 0 &&
+		(option.Freq != WEEKLY || option.Interval <= 1 && len(option.Bysetpos) == 0) {
+		issues = append(issues, LintIssue{LintIgnored, "WKST",
+			"WKST only matters with BYWEEKNO or FREQ=WEEKLY with INTERVAL or BYSETPOS"})
+	}
+	if option.Computus != Western && len(option.Byeaster) == 0 {
+		issues = append(issues, LintIssue{LintIgnored, "COMPUTUS", "COMPUTUS only matters with BYEASTER"})
+	}
+	return issues
+}
+
+// lintPart checks the values of a BY* part one by one.
+type lintPart struct {
+	param  string
+	values []int
+	match  func(v int) bool
+	format func(v int) string
+	reason string
+}
+
+func lintValues(option ROption) []lintPart {
+	var parts []lintPart
+	months := option.Bymonth
+	if len(months) == 0 {
+		months = rang(1, 13)
+	}
+
+	parts = append(parts, lintPart{
+		param:  "BYMONTHDAY",
+		values: option.Bymonthday,
+		match: func(v int) bool {
+			for _, m := range months {
+				// 2000 is a leap year, so February has 29 days.
+				if daysIn(time.Month(m), 2000) >= v && daysIn(time.Month(m), 2000) >= -v {
+					return true
+				}
+			}
+			return false
+		},
+		reason: "never matches in the selected months",
+	})
+
+	if len(option.Bymonth) != 0 {
+		parts = append(parts, lintPart{
+			param:  "BYYEARDAY",
+			values: option.Byyearday,
+			match: func(v int) bool {
+				for _, mask := range [][]int{M365MASK[:365], M366MASK[:366]} {
+					i := v - 1
+					if v < 0 {
+						i = len(mask) + v
+					}
+					if i >= 0 && i < len(mask) && contains(option.Bymonth, mask[i]) {
+						return true
+					}
+				}
+				return false
+			},
+			reason: "never matches in the selected months",
+		})
+	}
+
+	if option.Freq == MONTHLY || option.Freq == YEARLY && len(option.Bymonth) != 0 {
+		var nth []int
+		for i, w := range option.Byweekday {
+			if w.n != 0 {
+				nth = append(nth, i)
+			}
+		}
+		parts = append(parts, lintPart{
+			param:  "BYDAY",
+			values: nth,
+			match: func(i int) bool {
+				n := option.Byweekday[i].n
+				return n <= 5 && n >= -5
+			},
+			format: func(i int) string { return option.Byweekday[i].String() },
+			reason: "exceeds the number of weeks in a month",
+		})
+	}
+
+	// With a fixed INTERVAL, the period only visits some months, weekdays,
+	// hours, minutes or seconds.
+	if !option.Dtstart.IsZero() && option.Interval > 1 {
+		reachable := func(start, modulus int) func(v int) bool {
+			step := int(gcd(int64(option.Interval), int64(modulus)))
+			return func(v int) bool { return pymod(v-start, step) == 0 }
+		}
+		reason := fmt.Sprintf("is never reached with FREQ=%s;INTERVAL=%d from DTSTART", option.Freq, option.Interval)
+		dtstart := option.Dtstart
+		switch option.Freq {
+		case MONTHLY:
+			parts = append(parts, lintPart{param: "BYMONTH", values: option.Bymonth,
+				match: reachable(int(dtstart.Month()), 12), reason: reason})
+		case DAILY:
+			var days []int
+			for _, w := range option.Byweekday {
+				days = append(days, w.weekday)
+			}
+			parts = append(parts, lintPart{param: "BYDAY", values: days,
+				match:  reachable(toPyWeekday(dtstart.Weekday()), 7),
+				format: func(v int) string { return Weekday{weekday: v}.String() }, reason: reason})
+		case HOURLY:
+			parts = append(parts, lintPart{param: "BYHOUR", values: option.Byhour,
+				match: reachable(dtstart.Hour(), 24), reason: reason})
+		case MINUTELY:
+			parts = append(parts, lintPart{param: "BYMINUTE", values: option.Byminute,
+				match: reachable(dtstart.Minute(), 60), reason: reason})
+		case SECONDLY:
+			parts = append(parts, lintPart{param: "BYSECOND", values: option.Bysecond,
+				match: reachable(dtstart.Second(), 60), reason: reason})
+		}
+	}
+
+	for i := range parts {
+		if parts[i].format == nil {
+			parts[i].format = func(v int) string { return fmt.Sprint(v) }
+		}
+	}
+	return parts
+}
+
+// anyDayMatches reports whether some day of a Gregorian cycle passes the
+// day-level BY* parts of option, ignoring BYEASTER.
+func anyDayMatches(option ROption) bool {
+	option.Byeaster = nil
+	r := buildRRule(option)
+	ii := iterInfo{rrule: &r}
+	year := r.dtstart.Year()
+	for y := year; y < year+lintYears; y++ {
+		for m := time.January; m <= time.December; m++ {
+			ii.rebuild(y, m)
+			for i := ii.mrange[m-1]; i < ii.mrange[m]; i++ {
+				if !ii.excluded(i) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Lint reports the issues of the set's RRULE, as Lint does, and dates of
+// the set that have no effect.
+func (set *Set) Lint() []LintIssue {
+	issues := []LintIssue{}
+	if set.rrule != nil {
+		option := set.rrule.OrigOptions
+		option.Dtstart = set.rrule.dtstart
+		issues = append(issues, Lint(option)...)
+	}
+
+	if set.rrule == nil {
+		included := 0
+		for _, rdate := range set.rdate {
+			if !timeContains(set.exdate, rdate) {
+				included++
+			}
+		}
+		if included == 0 {
+			issues = append(issues, LintIssue{LintEmpty, "", "the set has no RRULE and no RDATE that is not excluded"})
+		}
+	}
+
+	if set.rrule != nil {
+		dtstart := set.rrule.dtstart
+		for _, exdate := range set.exdate {
+			if exdate.Before(dtstart) && !timeContains(set.rdate, exdate) {
+				issues = append(issues, LintIssue{LintIgnored, "EXDATE",
+					fmt.Sprintf("EXDATE %s is before DTSTART", timeToStr(exdate))})
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,89 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLint(t *testing.T) {
+	dtstart := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		rule string
+		want []LintIssue
+	}{
+		{"FREQ=WEEKLY;BYDAY=MO,WE", []LintIssue{}},
+		{"FREQ=MONTHLY;BYMONTHDAY=31;BYMONTH=2", []LintIssue{
+			{LintNeverMatches, "BYMONTHDAY", "BYMONTHDAY=31 never matches in the selected months"},
+			{LintEmpty, "BYMONTHDAY", "no value of BYMONTHDAY can match"},
+		}},
+		{"FREQ=YEARLY;BYWEEKNO=53;BYMONTH=6", []LintIssue{
+			{LintEmpty, "", "no day matches all the BY* parts"},
+		}},
+		{"FREQ=YEARLY;BYWEEKNO=53;BYMONTH=1", []LintIssue{}},
+		{"FREQ=DAILY;COUNT=3;UNTIL=20190101T000000Z", []LintIssue{
+			{LintDisallowed, "UNTIL", "COUNT and UNTIL must not both be set"},
+			{LintEmpty, "UNTIL", "UNTIL is before DTSTART"},
+		}},
+		{"FREQ=MONTHLY;BYWEEKNO=1;BYYEARDAY=1", []LintIssue{
+			{LintDisallowed, "BYWEEKNO", "BYWEEKNO is only allowed with FREQ=YEARLY"},
+			{LintDisallowed, "BYYEARDAY", "BYYEARDAY is not allowed with FREQ=MONTHLY"},
+		}},
+		{"FREQ=WEEKLY;BYDAY=+1MO;WKST=SU", []LintIssue{
+			{LintDisallowed, "BYDAY", "BYDAY=+1MO: numeric values are only allowed with FREQ=MONTHLY or YEARLY"},
+			{LintIgnored, "BYDAY", "BYDAY=+1MO: the number is ignored with FREQ=WEEKLY"},
+			{LintIgnored, "WKST", "WKST only matters with BYWEEKNO or FREQ=WEEKLY with INTERVAL or BYSETPOS"},
+		}},
+		{"FREQ=MONTHLY;BYDAY=+6FR,-1FR", []LintIssue{
+			{LintNeverMatches, "BYDAY", "BYDAY=+6FR exceeds the number of weeks in a month"},
+		}},
+		{"FREQ=MONTHLY;INTERVAL=12;BYMONTH=2", []LintIssue{
+			{LintNeverMatches, "BYMONTH", "BYMONTH=2 is never reached with FREQ=MONTHLY;INTERVAL=12 from DTSTART"},
+			{LintEmpty, "BYMONTH", "no value of BYMONTH can match"},
+		}},
+		{"FREQ=HOURLY;INTERVAL=12;BYHOUR=3,9", []LintIssue{
+			{LintNeverMatches, "BYHOUR", "BYHOUR=3 is never reached with FREQ=HOURLY;INTERVAL=12 from DTSTART"},
+		}},
+		{"FREQ=YEARLY;BYSETPOS=1", []LintIssue{
+			{LintDisallowed, "BYSETPOS", "BYSETPOS requires another BY* part"},
+		}},
+		{"FREQ=DAILY;BYHOUR=24", []LintIssue{
+			{LintNeverMatches, "BYHOUR", "byhour must be between 0 and 23"},
+		}},
+	}
+	for _, c := range cases {
+		option, err := StrToROption(c.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		option.Dtstart = dtstart
+		issues := Lint(*option)
+		if len(issues) != len(c.want) {
+			t.Errorf("Lint(%q) = %v, want %v", c.rule, issues, c.want)
+			continue
+		}
+		for i := range issues {
+			if issues[i] != c.want[i] {
+				t.Errorf("Lint(%q)[%d] = %v, want %v", c.rule, i, issues[i], c.want[i])
+			}
+		}
+	}
+}
+
+func TestSetLint(t *testing.T) {
+	set := &Set{}
+	set.RDate(time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC))
+	want := LintIssue{LintEmpty, "", "the set has no RRULE and no RDATE that is not excluded"}
+	if issues := set.Lint(); len(issues) != 1 || issues[0] != want {
+		t.Errorf("get %v, want %v", issues, want)
+	}
+
+	set, err := StrToRRuleSet("DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY\nEXDATE:20191231T090000Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = LintIssue{LintIgnored, "EXDATE", "EXDATE 20191231T090000Z is before DTSTART"}
+	if issues := set.Lint(); len(issues) != 1 || issues[0] != want {
+		t.Errorf("get %v, want %v", issues, want)
+	}
+}
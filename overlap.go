@@ -0,0 +1,204 @@
+package rrule
+
+import "time"
+
+// Event is a recurrence whose occurrences last for Duration.
+// Each occurrence covers the half-open interval [start, start+Duration).
+type Event struct {
+	Set      *Set
+	Duration time.Duration
+}
+
+// Conflict reports a pair of clashing occurrences.
+// A and B are the indexes of the events in the slice passed to Conflicts or
+// FirstConflict, with A < B. AStart and BStart are the starts of the
+// occurrences that overlap.
+type Conflict struct {
+	A, B           int
+	AStart, BStart time.Time
+}
+
+// Start returns the beginning of the overlapping interval.
+func (c Conflict) Start() time.Time {
+	if c.AStart.After(c.BStart) {
+		return c.AStart
+	}
+	return c.BStart
+}
+
+// Conflicts returns all the pairs of occurrences of different events that
+// overlap within [after, before), ordered by the start of the overlap.
+//
+// A zero before means no limit, so that Conflicts runs until the events are
+// exhausted, 290 years after DTSTART for an RRULE without COUNT and UNTIL.
+func Conflicts(after, before time.Time, events ...Event) []Conflict {
+	result := []Conflict{}
+	sweepConflicts(events, after, before, func(c Conflict) bool {
+		result = append(result, c)
+		return true
+	})
+	return result
+}
+
+// FirstConflict returns the earliest pair of overlapping occurrences of
+// different events after the given datetime instance, and false if there is
+// none before the given limit.
+//
+// A zero before means no limit. When every event is periodic (see
+// CycleLength), the search stops once the combined cycle of all events has
+// been covered, since any later conflict would repeat an earlier one.
+// Otherwise the search runs until the events are exhausted.
+func FirstConflict(after, before time.Time, events ...Event) (Conflict, bool) {
+	if horizon, ok := periodicHorizon(events, after); ok && (before.IsZero() || horizon.Before(before)) {
+		before = horizon
+	}
+	var result Conflict
+	found := false
+	sweepConflicts(events, after, before, func(c Conflict) bool {
+		result, found = c, true
+		return false
+	})
+	return result, found
+}
+
+// CycleLength returns the period after which the occurrences of the set
+// repeat, and false if the set is not known to be periodic.
+//
+// Only sets whose RRULE is evaluated in UTC with a WEEKLY or finer frequency
+// and no BYMONTH, BYMONTHDAY, BYYEARDAY, BYWEEKNO, BYEASTER or nth-weekday
+// parts qualify, because the length of months and years and daylight saving
+// time transitions break any fixed period. Sets without an RRULE are finite
+// and have a cycle of one second.
+func (set *Set) CycleLength() (time.Duration, bool) {
+	r := set.rrule
+	if r == nil {
+		return time.Second, true
+	}
+	if r.freq < WEEKLY || r.dtstart.Location().String() != "UTC" ||
+		len(r.bymonth) != 0 || len(r.bymonthday) != 0 || len(r.bynmonthday) != 0 ||
+		len(r.byyearday) != 0 || len(r.byweekno) != 0 || len(r.bynweekday) != 0 ||
+		len(r.byeaster) != 0 {
+		return 0, false
+	}
+	units := map[Frequency]int64{
+		WEEKLY: 7 * 86400, DAILY: 86400, HOURLY: 3600, MINUTELY: 60, SECONDLY: 1,
+	}
+	// BYDAY, BYHOUR, BYMINUTE and BYSECOND all repeat weekly.
+	seconds := lcm(int64(r.interval)*units[r.freq], 7*86400)
+	return time.Duration(seconds) * time.Second, true
+}
+
+// periodicHorizon returns the instant after which no new conflicts can
+// appear when searching from after, if every event is periodic.
+func periodicHorizon(events []Event, after time.Time) (time.Time, bool) {
+	var cycle int64 = 1
+	phase := after
+	var maxDuration time.Duration
+	later := func(t time.Time) {
+		if phase.IsZero() || t.After(phase) {
+			phase = t
+		}
+	}
+	for _, e := range events {
+		length, ok := e.Set.CycleLength()
+		if !ok {
+			return time.Time{}, false
+		}
+		seconds := int64(length / time.Second)
+		if cycle/gcd(cycle, seconds) > int64((1<<63-1)/time.Second)/seconds {
+			// The combined cycle does not fit in a time.Duration.
+			return time.Time{}, false
+		}
+		cycle = lcm(cycle, seconds)
+		if e.Duration > maxDuration {
+			maxDuration = e.Duration
+		}
+		// The pattern only becomes periodic once every rule has started
+		// and the last explicit date has passed.
+		if e.Set.rrule != nil {
+			later(e.Set.rrule.dtstart)
+		}
+		for _, t := range e.Set.rdate {
+			later(t)
+		}
+		for _, t := range e.Set.exdate {
+			later(t)
+		}
+	}
+	return phase.Add(time.Duration(cycle) * time.Second).Add(maxDuration).Add(time.Second), true
+}
+
+type activeOccurrence struct {
+	event      int
+	start, end time.Time
+}
+
+// sweepConflicts merges the occurrences of all events in chronological order
+// and calls yield for every overlap within [after, before) until yield
+// returns false. A zero before means no limit.
+func sweepConflicts(events []Event, after, before time.Time, yield func(Conflict) bool) {
+	var maxDuration time.Duration
+	for _, e := range events {
+		if e.Duration > maxDuration {
+			maxDuration = e.Duration
+		}
+	}
+	// Occurrences starting before after may still be running at after.
+	from := after.Add(-maxDuration)
+
+	gens := make([]Next, len(events))
+	for i, e := range events {
+		next := e.Set.Iterator()
+		gens[i] = func() (time.Time, bool) {
+			for {
+				dt, ok := next()
+				if !ok || !dt.Before(from) {
+					return dt, ok
+				}
+			}
+		}
+	}
+	h := newGenHeap(gens...)
+
+	var active []activeOccurrence
+	for {
+		start, event, ok := h.nextSource()
+		if !ok || !before.IsZero() && !start.Before(before) {
+			return
+		}
+
+		// Drop occurrences that ended before this one started. Occurrences
+		// sharing the same start always clash, even with zero duration.
+		kept := active[:0]
+		for _, a := range active {
+			if a.end.After(start) || a.start.Equal(start) {
+				kept = append(kept, a)
+			}
+		}
+		active = kept
+
+		end := start.Add(events[event].Duration)
+		for _, a := range active {
+			if a.event == event {
+				continue
+			}
+			// The overlap is [start, min(a.end, end)); skip it if it is
+			// entirely before the window.
+			overlapEnd := end
+			if a.end.Before(overlapEnd) {
+				overlapEnd = a.end
+			}
+			if overlapEnd.Before(after) || overlapEnd.Equal(after) && overlapEnd.After(start) {
+				continue
+			}
+			c := Conflict{A: a.event, B: event, AStart: a.start, BStart: start}
+			if c.A > c.B {
+				c.A, c.B, c.AStart, c.BStart = c.B, c.A, c.BStart, c.AStart
+			}
+			if !yield(c) {
+				return
+			}
+		}
+		active = append(active, activeOccurrence{event, start, end})
+	}
+}
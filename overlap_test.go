@@ -0,0 +1,111 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestSet(t *testing.T, option ROption) *Set {
+	t.Helper()
+	r, err := NewRRule(option)
+	if err != nil {
+		t.Fatalf("NewRRule(%v) returned error: %v", option, err)
+	}
+	set := &Set{}
+	set.RRule(r)
+	return set
+}
+
+func TestConflicts(t *testing.T) {
+	daily := newTestSet(t, ROption{Freq: DAILY, Count: 5,
+		Dtstart: time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC)})
+	weekly := newTestSet(t, ROption{Freq: WEEKLY, Count: 2, Byweekday: []Weekday{WE},
+		Dtstart: time.Date(1997, 9, 1, 9, 30, 0, 0, time.UTC)})
+	value := Conflicts(time.Date(1997, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(1997, 10, 1, 0, 0, 0, 0, time.UTC),
+		Event{daily, time.Hour}, Event{weekly, time.Hour})
+	want := []Conflict{{A: 0, B: 1,
+		AStart: time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC),
+		BStart: time.Date(1997, 9, 3, 9, 30, 0, 0, time.UTC)}}
+	if len(value) != len(want) || value[0] != want[0] {
+		t.Errorf("get %v, want %v", value, want)
+	}
+	if start := value[0].Start(); start != want[0].BStart {
+		t.Errorf("get %v, want %v", start, want[0].BStart)
+	}
+}
+
+func TestConflictsAdjacent(t *testing.T) {
+	a := newTestSet(t, ROption{Freq: DAILY, Count: 3,
+		Dtstart: time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC)})
+	b := newTestSet(t, ROption{Freq: DAILY, Count: 3,
+		Dtstart: time.Date(1997, 9, 1, 10, 0, 0, 0, time.UTC)})
+	value := Conflicts(time.Date(1997, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(1997, 10, 1, 0, 0, 0, 0, time.UTC),
+		Event{a, time.Hour}, Event{b, time.Hour})
+	if len(value) != 0 {
+		t.Errorf("get %v, want no conflicts", value)
+	}
+}
+
+func TestConflictsWindow(t *testing.T) {
+	a := newTestSet(t, ROption{Freq: DAILY, Count: 3,
+		Dtstart: time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC)})
+	b := &Set{}
+	b.RDate(time.Date(1997, 9, 2, 8, 0, 0, 0, time.UTC))
+	// The clash starts before the window but lasts into it.
+	value := Conflicts(time.Date(1997, 9, 2, 9, 30, 0, 0, time.UTC), time.Date(1997, 10, 1, 0, 0, 0, 0, time.UTC),
+		Event{a, time.Hour}, Event{b, 2 * time.Hour})
+	want := Conflict{A: 0, B: 1,
+		AStart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		BStart: time.Date(1997, 9, 2, 8, 0, 0, 0, time.UTC)}
+	if len(value) != 1 || value[0] != want {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestFirstConflict(t *testing.T) {
+	a := newTestSet(t, ROption{Freq: WEEKLY, Interval: 2, Byweekday: []Weekday{MO},
+		Dtstart: time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC)})
+	b := newTestSet(t, ROption{Freq: WEEKLY, Interval: 3, Byweekday: []Weekday{MO},
+		Dtstart: time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC)})
+	value, ok := FirstConflict(time.Date(1997, 9, 1, 0, 0, 0, 0, time.UTC), time.Time{},
+		Event{a, time.Hour}, Event{b, time.Hour})
+	want := time.Date(1997, 9, 29, 9, 0, 0, 0, time.UTC)
+	if !ok || value.AStart != want || value.BStart != want {
+		t.Errorf("get %v, %v, want %v", value, ok, want)
+	}
+}
+
+func TestFirstConflictPeriodicNone(t *testing.T) {
+	a := newTestSet(t, ROption{Freq: WEEKLY, Interval: 2, Byweekday: []Weekday{MO},
+		Dtstart: time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC)})
+	b := newTestSet(t, ROption{Freq: WEEKLY, Interval: 2, Byweekday: []Weekday{MO},
+		Dtstart: time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC)})
+	c := newTestSet(t, ROption{Freq: DAILY, Byhour: []int{12},
+		Dtstart: time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC)})
+	value, ok := FirstConflict(time.Date(1997, 9, 1, 0, 0, 0, 0, time.UTC), time.Time{},
+		Event{a, time.Hour}, Event{b, time.Hour}, Event{c, time.Hour})
+	if ok {
+		t.Errorf("get %v, want no conflict", value)
+	}
+}
+
+func TestCycleLength(t *testing.T) {
+	cases := []struct {
+		option ROption
+		want   time.Duration
+		ok     bool
+	}{
+		{ROption{Freq: DAILY}, 7 * 24 * time.Hour, true},
+		{ROption{Freq: WEEKLY, Interval: 3}, 21 * 24 * time.Hour, true},
+		{ROption{Freq: HOURLY, Interval: 5}, 35 * 24 * time.Hour, true},
+		{ROption{Freq: MONTHLY}, 0, false},
+		{ROption{Freq: DAILY, Bymonthday: []int{1}}, 0, false},
+	}
+	for _, c := range cases {
+		c.option.Dtstart = time.Date(1997, 9, 1, 9, 0, 0, 0, time.UTC)
+		value, ok := newTestSet(t, c.option).CycleLength()
+		if value != c.want || ok != c.ok {
+			t.Errorf("CycleLength(%v) = %v, %v, want %v, %v", c.option.RRuleString(), value, ok, c.want, c.ok)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ValidateRFC5545 checks option against the MUST rules of RFC 5545 section
+// 3.3.10, which NewRRule does not enforce: COUNT and UNTIL together, BY*
+// parts not allowed with FREQ, BYSETPOS alone, BYEASTER and COMPUTUS
+// (extensions of this package) and an UNTIL whose time zone does not match
+// DTSTART. It returns a *ValidationError listing every violation, or nil.
+func ValidateRFC5545(option ROption) error {
+	errs := append(boundsErrors(option), rfcViolations(option)...)
+	if err := untilViolation(option); err != nil {
+		errs = append(errs, err)
+	}
+	return validationError(errs)
+}
+
+// NewRRuleRFC5545 is same as NewRRule, but rejects options that violate
+// RFC 5545 as ValidateRFC5545 does.
+func NewRRuleRFC5545(arg ROption) (*RRule, error) {
+	if err := ValidateRFC5545(arg); err != nil {
+		return nil, err
+	}
+	r := buildRRule(arg)
+	return &r, nil
+}
+
+func validationError(errs []*ParseError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: errs}
+}
+
+// rfcViolations returns the combinations of rule parts in option that
+// RFC 5545 forbids, regardless of DTSTART and UNTIL values.
+func rfcViolations(option ROption) []*ParseError {
+	var errs []*ParseError
+	violate := func(param, value, message string) {
+		errs = append(errs, &ParseError{Param: param, Value: value, Kind: ErrNotAllowed, Err: errors.New(message)})
+	}
+	if option.Count != 0 && !option.Until.IsZero() {
+		violate("UNTIL", "", "COUNT and UNTIL must not both be set")
+	}
+	if len(option.Byweekno) != 0 && option.Freq != YEARLY {
+		violate("BYWEEKNO", "", "BYWEEKNO is only allowed with FREQ=YEARLY")
+	}
+	if len(option.Byyearday) != 0 && (option.Freq == DAILY || option.Freq == WEEKLY || option.Freq == MONTHLY) {
+		violate("BYYEARDAY", "", fmt.Sprintf("BYYEARDAY is not allowed with FREQ=%s", option.Freq))
+	}
+	if len(option.Bymonthday) != 0 && option.Freq == WEEKLY {
+		violate("BYMONTHDAY", "", "BYMONTHDAY is not allowed with FREQ=WEEKLY")
+	}
+	for _, w := range option.Byweekday {
+		if w.n == 0 {
+			continue
+		}
+		if option.Freq != MONTHLY && option.Freq != YEARLY {
+			violate("BYDAY", w.String(), fmt.Sprintf("BYDAY=%s: numeric values are only allowed with FREQ=MONTHLY or YEARLY", w))
+		} else if option.Freq == YEARLY && len(option.Byweekno) != 0 {
+			violate("BYDAY", w.String(), fmt.Sprintf("BYDAY=%s: numeric values are not allowed with BYWEEKNO", w))
+		}
+	}
+	if len(option.Bysetpos) != 0 && len(option.Bymonth) == 0 && len(option.Bymonthday) == 0 &&
+		len(option.Byyearday) == 0 && len(option.Byweekno) == 0 && len(option.Byweekday) == 0 &&
+		len(option.Byhour) == 0 && len(option.Byminute) == 0 && len(option.Bysecond) == 0 &&
+		len(option.Byeaster) == 0 {
+		violate("BYSETPOS", "", "BYSETPOS requires another BY* part")
+	}
+	if len(option.Byeaster) != 0 {
+		violate("BYEASTER", "", "BYEASTER is not part of RFC 5545")
+	}
+	if option.Computus != Western {
+		violate("COMPUTUS", option.Computus.String(), "COMPUTUS is not part of RFC 5545")
+	}
+	return errs
+}
+
+// untilViolation checks that UNTIL is in UTC, unless it is a local time
+// like DTSTART, i.e. both share a location other than UTC.
+func untilViolation(option ROption) *ParseError {
+	if option.Until.IsZero() || option.Dtstart.IsZero() {
+		return nil
+	}
+	loc := option.Until.Location()
+	if loc == time.UTC || loc == option.Dtstart.Location() {
+		return nil
+	}
+	return &ParseError{Param: "UNTIL", Value: timeToStr(option.Until), Kind: ErrNotAllowed,
+		Err: errors.New("UNTIL must be in UTC or in the local time of DTSTART")}
+}
+
+// valueForm is the value type of a DTSTART or UNTIL value.
+type valueForm int
+
+const (
+	formUnknown valueForm = iota
+	formDate
+	formLocal
+	formUTC
+	formZoned
+)
+
+func (f valueForm) String() string {
+	return [...]string{"", "a date", "a local date-time", "a UTC date-time", "a date-time with TZID"}[f]
+}
+
+// formOf returns the value type of an iCalendar value, which may start with
+// parameters like "TZID=Europe/Paris:". Other values, e.g. ISO 8601 ones
+// accepted in Lenient mode, are of unknown type.
+func formOf(value string) valueForm {
+	zoned := strings.Contains(value, ":")
+	value = value[strings.LastIndex(value, ":")+1:]
+	switch {
+	case len(value) == len(DateFormat):
+		return formDate
+	case len(value) == len(DateTimeFormat) && strings.HasSuffix(value, "Z"):
+		return formUTC
+	case len(value) == len(LocalDateTimeFormat) && zoned:
+		return formZoned
+	case len(value) == len(LocalDateTimeFormat):
+		return formLocal
+	}
+	return formUnknown
+}
+
+// untilFormViolation checks that UNTIL has the value type required by the
+// value type of DTSTART.
+func untilFormViolation(dtstart, until valueForm) *ParseError {
+	want := dtstart
+	if want == formZoned {
+		want = formUTC
+	}
+	if dtstart == formUnknown || until == formUnknown || until == want {
+		return nil
+	}
+	return &ParseError{Param: "UNTIL", Kind: ErrNotAllowed,
+		Err: fmt.Errorf("UNTIL must be %s when DTSTART is %s", want, dtstart)}
+}
@@ -0,0 +1,87 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateRFC5545(t *testing.T) {
+	option := ROption{
+		Freq:      WEEKLY,
+		Dtstart:   time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC),
+		Count:     3,
+		Until:     time.Date(2020, 2, 1, 9, 0, 0, 0, time.UTC),
+		Byweekday: []Weekday{MO.Nth(1)},
+		Byhour:    []int{25},
+		Byeaster:  []int{0},
+	}
+	err := ValidateRFC5545(option)
+	var v *ValidationError
+	if !errors.As(err, &v) {
+		t.Fatalf("get %v, want a ValidationError", err)
+	}
+	want := []string{"BYHOUR", "UNTIL", "BYDAY", "BYEASTER"}
+	if len(v.Errors) != len(want) {
+		t.Fatalf("get %v, want %d violations", err, len(want))
+	}
+	for i, e := range v.Errors {
+		if e.Param != want[i] {
+			t.Errorf("get %v, want %v", e.Param, want[i])
+		}
+	}
+	if !errors.Is(err, ErrNotAllowed) || !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("get %v, want ErrNotAllowed and ErrOutOfRange", err)
+	}
+	if _, err := NewRRuleRFC5545(option); err == nil {
+		t.Error("get nil, want error")
+	}
+	if _, err := NewRRule(option); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("get %v, want ErrOutOfRange", err)
+	}
+
+	option = ROption{Freq: MONTHLY, Dtstart: option.Dtstart, Byweekday: []Weekday{MO.Nth(1)}}
+	if _, err := NewRRuleRFC5545(option); err != nil {
+		t.Errorf("get %v, want nil", err)
+	}
+}
+
+func TestParseRFC5545(t *testing.T) {
+	opts := ParseOptions{RFC5545: true}
+	cases := []struct {
+		str  string
+		want string
+	}{
+		{"DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY;UNTIL=20200201T090000Z", ""},
+		{"DTSTART;TZID=America/New_York:20200101T090000\nRRULE:FREQ=DAILY;UNTIL=20200201T090000Z", ""},
+		{"DTSTART:20200101\nRRULE:FREQ=DAILY;UNTIL=20200201", ""},
+		{"DTSTART:20200101\nRRULE:FREQ=DAILY;UNTIL=20200201T090000Z",
+			"line 2 RRULE: UNTIL must be a date when DTSTART is a date"},
+		{"DTSTART;TZID=America/New_York:20200101T090000\nRRULE:FREQ=DAILY;UNTIL=20200201T090000",
+			"line 2 RRULE: UNTIL must be a UTC date-time when DTSTART is a date-time with TZID"},
+		{"DTSTART:20200101T090000Z\nRRULE:FREQ=WEEKLY;COUNT=2;UNTIL=20200201T090000Z;BYMONTHDAY=1",
+			"line 2 RRULE: COUNT and UNTIL must not both be set; line 2 RRULE: BYMONTHDAY is not allowed with FREQ=WEEKLY"},
+	}
+	for _, c := range cases {
+		_, _, err := StrToRRuleSetWithOptions(c.str, opts)
+		if got := errString(err); got != c.want {
+			t.Errorf("StrToRRuleSetWithOptions(%q) = %q, want %q", c.str, got, c.want)
+		}
+		if _, err := StrToRRuleSet(c.str); err != nil {
+			t.Errorf("StrToRRuleSet(%q) = %v, want nil", c.str, err)
+		}
+	}
+
+	_, _, err := StrToRRuleWithOptions("FREQ=DAILY;BYWEEKNO=1", opts)
+	var v *ValidationError
+	if !errors.As(err, &v) || len(v.Errors) != 1 || v.Errors[0].Offset != 20 {
+		t.Errorf("get %v, want BYWEEKNO violation at offset 20", err)
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
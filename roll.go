@@ -0,0 +1,127 @@
+package rrule
+
+import "time"
+
+// RollConvention tells how Roll moves a time that falls on a non-working
+// day, as business day conventions do in finance.
+type RollConvention int
+
+// Roll conventions
+const (
+	// Unadjusted keeps the time as it is.
+	Unadjusted RollConvention = iota
+	// Following moves to the next working day.
+	Following
+	// Preceding moves to the previous working day.
+	Preceding
+	// ModifiedFollowing moves to the next working day, unless it is in
+	// another month, in which case it moves to the previous working day.
+	ModifiedFollowing
+	// ModifiedPreceding moves to the previous working day, unless it is in
+	// another month, in which case it moves to the next working day.
+	ModifiedPreceding
+)
+
+// maxRollDays bounds the search for a working day, so that a calendar
+// without working days cannot make Roll loop forever.
+const maxRollDays = 366
+
+// Roll moves dt to a working day of cal according to conv, keeping its
+// time of day. dt is returned as is if it is a working day, or if there is
+// no working day within a year in the direction of conv.
+func Roll(dt time.Time, cal Calendar, conv RollConvention) time.Time {
+	if conv == Unadjusted || cal.IsWorkingDay(dt) {
+		return dt
+	}
+	switch conv {
+	case Following:
+		return rollDays(dt, cal, 1)
+	case Preceding:
+		return rollDays(dt, cal, -1)
+	case ModifiedFollowing:
+		if rolled := rollDays(dt, cal, 1); rolled.Month() == dt.Month() {
+			return rolled
+		}
+		return rollDays(dt, cal, -1)
+	case ModifiedPreceding:
+		if rolled := rollDays(dt, cal, -1); rolled.Month() == dt.Month() {
+			return rolled
+		}
+		return rollDays(dt, cal, 1)
+	}
+	return dt
+}
+
+// rollDays returns the first working day after or before dt, depending on
+// the sign of step.
+func rollDays(dt time.Time, cal Calendar, step int) time.Time {
+	for i := 1; i <= maxRollDays; i++ {
+		if rolled := dt.AddDate(0, 0, i*step); cal.IsWorkingDay(rolled) {
+			return rolled
+		}
+	}
+	return dt
+}
+
+// RollIterator returns the times of next rolled to working days of cal
+// according to conv. They remain in chronological order, and times that
+// roll onto the same time are returned once.
+func RollIterator(next Next, cal Calendar, conv RollConvention) Next {
+	// Rolled times are buffered until no later time of next can roll
+	// before them.
+	var buffer []time.Time
+	var last time.Time
+	raw, ok := next()
+	return func() (time.Time, bool) {
+		for {
+			for ok && (len(buffer) == 0 || !buffer[0].Before(rollBound(raw, cal, conv))) {
+				buffer = insertTime(buffer, Roll(raw, cal, conv))
+				raw, ok = next()
+			}
+			if len(buffer) == 0 {
+				return time.Time{}, false
+			}
+			dt := buffer[0]
+			buffer = buffer[1:]
+			if last.IsZero() || !dt.Equal(last) {
+				last = dt
+				return dt, true
+			}
+		}
+	}
+}
+
+// rollBound returns a time before which no time from raw on rolls.
+func rollBound(raw time.Time, cal Calendar, conv RollConvention) time.Time {
+	switch conv {
+	case Unadjusted, Following:
+		return raw
+	}
+	// The other conventions roll at most to the previous working day.
+	year, month, day := Roll(raw, cal, Preceding).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, raw.Location())
+}
+
+// insertTime inserts t into the sorted s.
+func insertTime(s []time.Time, t time.Time) []time.Time {
+	i := len(s)
+	for i > 0 && t.Before(s[i-1]) {
+		i--
+	}
+	s = append(s, time.Time{})
+	copy(s[i+1:], s[i:])
+	s[i] = t
+	return s
+}
+
+// Rolled returns an iterator over the occurrences of r rolled to working
+// days of cal, as RollIterator does.
+func (r *RRule) Rolled(cal Calendar, conv RollConvention) Next {
+	return RollIterator(r.Iterator(), cal, conv)
+}
+
+// Rolled returns an iterator over the occurrences of the set rolled to
+// working days of cal, as RollIterator does. EXDATEs apply before rolling.
+func (set *Set) Rolled(cal Calendar, conv RollConvention) Next {
+	return RollIterator(set.Iterator(), cal, conv)
+}
@@ -0,0 +1,67 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoll(t *testing.T) {
+	cal := NewHolidayCalendar()
+	cal.AddRule(FixedHoliday(time.June, 1))
+	tests := []struct {
+		dt   time.Time
+		conv RollConvention
+		want time.Time
+	}{
+		{time.Date(2020, 5, 30, 9, 0, 0, 0, time.UTC), Unadjusted, time.Date(2020, 5, 30, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2020, 5, 30, 9, 0, 0, 0, time.UTC), Following, time.Date(2020, 6, 2, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2020, 5, 30, 9, 0, 0, 0, time.UTC), Preceding, time.Date(2020, 5, 29, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2020, 5, 30, 9, 0, 0, 0, time.UTC), ModifiedFollowing, time.Date(2020, 5, 29, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2020, 5, 23, 9, 0, 0, 0, time.UTC), ModifiedFollowing, time.Date(2020, 5, 25, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC), ModifiedPreceding, time.Date(2020, 6, 2, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2020, 6, 2, 9, 0, 0, 0, time.UTC), Preceding, time.Date(2020, 6, 2, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		if got := Roll(test.dt, cal, test.conv); got != test.want {
+			t.Errorf("%v %d: get %v, want %v", test.dt, test.conv, got, test.want)
+		}
+	}
+}
+
+func TestRollIterator(t *testing.T) {
+	cal := NewHolidayCalendar()
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 7, Dtstart: time.Date(2020, 5, 25, 9, 0, 0, 0, time.UTC)})
+	// Saturday 30 and Sunday 31 roll back onto Friday 29.
+	want := []time.Time{
+		time.Date(2020, 5, 25, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 5, 26, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 5, 27, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 5, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 5, 29, 9, 0, 0, 0, time.UTC),
+	}
+	if got := all(r.Rolled(cal, ModifiedFollowing)); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+
+	set := &Set{}
+	set.RDate(time.Date(2020, 5, 30, 10, 0, 0, 0, time.UTC))
+	set.RDate(time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC))
+	set.RDate(time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC))
+	want = []time.Time{
+		time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC),
+	}
+	if got := all(set.Rolled(cal, Following)); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+	set.RDate(time.Date(2020, 6, 2, 8, 0, 0, 0, time.UTC))
+	want = []time.Time{
+		time.Date(2020, 5, 29, 10, 0, 0, 0, time.UTC),
+		time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2020, 6, 2, 8, 0, 0, 0, time.UTC),
+	}
+	if got := all(set.Rolled(cal, Preceding)); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -51,6 +53,20 @@ const (
 	SECONDLY
 )
 
+// Computus is a method to compute the date of Easter, from which BYEASTER
+// counts.
+type Computus int
+
+// Computus methods
+const (
+	// Western is the Gregorian computus of the Catholic and Protestant
+	// churches.
+	Western Computus = iota
+	// Orthodox is the Julian computus of the Orthodox churches, with the
+	// resulting date converted to the Gregorian calendar.
+	Orthodox
+)
+
 // Weekday specifying the nth weekday.
 // Field N could be positive or negative (like MO(+2) or MO(-3).
 // Not specifying N (0) is the same as specifying +1.
@@ -88,6 +104,11 @@ var (
 )
 
 // ROption offers options to construct a RRule instance
+//
+// Dates follow the proleptic Gregorian calendar of package time: its rules
+// also apply before 1582, and years go on past 9999 and before 1, year 0
+// being 1 BC. A rule with a zero Until and no Count ends 290 years after
+// Dtstart.
 type ROption struct {
 	Freq       Frequency
 	Dtstart    time.Time
@@ -105,10 +126,30 @@ type ROption struct {
 	Byminute   []int
 	Bysecond   []int
 	Byeaster   []int
+	// Computus selects the date of Easter for Byeaster. It is serialized as
+	// the COMPUTUS rule part, an extension like BYEASTER.
+	Computus Computus
+	// Extensions holds X- rule parts as NAME=VALUE, kept for round trips.
+	// They do not affect the recurrence.
+	Extensions []string
+	// Subsecond keeps the fractional second of Dtstart and Until instead of
+	// truncating them to whole seconds, so that every occurrence carries
+	// the fractional second of Dtstart. It is not part of the RRULE string.
+	Subsecond bool
+}
+
+// truncate drops the fractional second of t unless Subsecond is set.
+func (option *ROption) truncate(t time.Time) time.Time {
+	if option.Subsecond {
+		return t
+	}
+	return t.Truncate(time.Second)
 }
 
 // RRule offers a small, complete, and very fast, implementation of the recurrence rules
 // documented in the iCalendar RFC, including support for caching of results.
+// Iterating does not modify an RRule, so it can be shared between goroutines
+// as long as DTStart and Until are not called; see WithDTStart and WithUntil.
 type RRule struct {
 	OrigOptions             ROption
 	Options                 ROption
@@ -129,8 +170,9 @@ type RRule struct {
 	byminute                []int
 	bysecond                []int
 	byeaster                []int
+	computus                Computus
 	timeset                 []time.Time
-	len                     int
+	cache                   *occurrenceCache
 }
 
 // NewRRule construct a new RRule instance
@@ -163,15 +205,14 @@ func buildRRule(arg ROption) RRule {
 	if arg.Dtstart.IsZero() {
 		arg.Dtstart = time.Now().UTC()
 	}
-	arg.Dtstart = arg.Dtstart.Truncate(time.Second)
+	arg.Dtstart = arg.truncate(arg.Dtstart)
 	r.dtstart = arg.Dtstart
 
 	// UNTIL
 	if arg.Until.IsZero() {
-		// add largest representable duration (approximately 290 years).
-		r.until = r.dtstart.Add(time.Duration(1<<63 - 1))
+		r.until = r.dtstart.AddDate(defaultUntilYears, 0, 0)
 	} else {
-		arg.Until = arg.Until.Truncate(time.Second)
+		arg.Until = arg.truncate(arg.Until)
 		r.until = arg.Until
 	}
 
@@ -197,6 +238,7 @@ func buildRRule(arg ROption) RRule {
 	r.bymonth = arg.Bymonth
 	r.byyearday = arg.Byyearday
 	r.byeaster = arg.Byeaster
+	r.computus = arg.Computus
 	for _, mday := range arg.Bymonthday {
 		if mday > 0 {
 			r.bymonthday = append(r.bymonthday, mday)
@@ -242,7 +284,7 @@ func buildRRule(arg ROption) RRule {
 		for _, hour := range r.byhour {
 			for _, minute := range r.byminute {
 				for _, second := range r.bysecond {
-					r.timeset = append(r.timeset, time.Date(1, 1, 1, hour, minute, second, 0, r.dtstart.Location()))
+					r.timeset = append(r.timeset, time.Date(1, 1, 1, hour, minute, second, r.dtstart.Nanosecond(), r.dtstart.Location()))
 				}
 			}
 		}
@@ -258,6 +300,15 @@ func buildRRule(arg ROption) RRule {
 // as going outside these bounds trivially will never have any dates. This can catch
 // obvious user error.
 func validateBounds(arg ROption) error {
+	if errs := boundsErrors(arg); len(errs) != 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// boundsErrors returns every value of arg outside the boundaries defined in
+// RFC 5545.
+func boundsErrors(arg ROption) []*ParseError {
 	bounds := []struct {
 		field     []int
 		param     string
@@ -274,13 +325,15 @@ func validateBounds(arg ROption) error {
 		{arg.Bysetpos, "bysetpos", []int{1, 366}, true},
 	}
 
-	checkBounds := func(param string, value int, bounds []int, plusMinus bool) error {
+	var errs []*ParseError
+	checkBounds := func(param string, value int, bounds []int, plusMinus bool) *ParseError {
 		if !(value >= bounds[0] && value <= bounds[1]) && (!plusMinus || !(value <= -bounds[0] && value >= -bounds[1])) {
 			plusMinusBounds := ""
 			if plusMinus {
 				plusMinusBounds = fmt.Sprintf(" or %d and %d", -bounds[0], -bounds[1])
 			}
-			return fmt.Errorf("%s must be between %d and %d%s", param, bounds[0], bounds[1], plusMinusBounds)
+			return &ParseError{Param: strings.ToUpper(param), Value: strconv.Itoa(value), Kind: ErrOutOfRange,
+				Err: fmt.Errorf("%s must be between %d and %d%s", param, bounds[0], bounds[1], plusMinusBounds)}
 		}
 		return nil
 	}
@@ -288,7 +341,7 @@ func validateBounds(arg ROption) error {
 	for _, b := range bounds {
 		for _, value := range b.field {
 			if err := checkBounds(b.param, value, b.bound, b.plusMinus); err != nil {
-				return err
+				errs = append(errs, err)
 			}
 		}
 	}
@@ -297,15 +350,22 @@ func validateBounds(arg ROption) error {
 	// of the month/year.
 	for _, w := range arg.Byweekday {
 		if w.n > 53 || w.n < -53 {
-			return errors.New("byday must be between 1 and 53 or -1 and -53")
+			errs = append(errs, &ParseError{Param: "BYDAY", Value: w.String(), Kind: ErrOutOfRange,
+				Err: errors.New("byday must be between 1 and 53 or -1 and -53")})
 		}
 	}
 
 	if arg.Interval < 0 {
-		return errors.New("interval must be greater than 0")
+		errs = append(errs, &ParseError{Param: "INTERVAL", Value: strconv.Itoa(arg.Interval), Kind: ErrOutOfRange,
+			Err: errors.New("interval must be greater than 0")})
 	}
 
-	return nil
+	if arg.Computus != Western && arg.Computus != Orthodox {
+		errs = append(errs, &ParseError{Param: "COMPUTUS", Value: strconv.Itoa(int(arg.Computus)), Kind: ErrOutOfRange,
+			Err: errors.New("computus must be Western or Orthodox")})
+	}
+
+	return errs
 }
 
 type iterInfo struct {
@@ -324,11 +384,12 @@ type iterInfo struct {
 	wnomask     []int
 	nwdaymask   []int
 	eastermask  []int
+	ranges      [][2]int // buffer for the ranges of nwdaymask
 }
 
 func (info *iterInfo) rebuild(year int, month time.Month) {
 	// Every mask is 7 days longer to handle cross-year weekly periods.
-	if year != info.lastyear {
+	if year != info.lastyear || info.yearlen == 0 {
 		info.yearlen = 365 + isLeap(year)
 		info.nextyearlen = 365 + isLeap(year+1)
 		info.firstyday = time.Date(
@@ -348,9 +409,9 @@ func (info *iterInfo) rebuild(year int, month time.Month) {
 			info.mrange = M366RANGE
 		}
 		if len(info.rrule.byweekno) == 0 {
-			info.wnomask = nil
+			info.wnomask = info.wnomask[:0]
 		} else {
-			info.wnomask = make([]int, info.yearlen+7)
+			info.wnomask = zeroed(info.wnomask, info.yearlen+7)
 			firstwkst := pymod(7-info.yearweekday+info.rrule.wkst, 7)
 			no1wkst := firstwkst
 			var wyearlen int
@@ -439,22 +500,23 @@ func (info *iterInfo) rebuild(year int, month time.Month) {
 		}
 	}
 	if len(info.rrule.bynweekday) != 0 && (month != info.lastmonth || year != info.lastyear) {
-		var ranges [][]int
+		ranges := info.ranges[:0]
 		if info.rrule.freq == YEARLY {
 			if len(info.rrule.bymonth) != 0 {
 				for _, month := range info.rrule.bymonth {
-					ranges = append(ranges, info.mrange[month-1:month+1])
+					ranges = append(ranges, [2]int{info.mrange[month-1], info.mrange[month]})
 				}
 			} else {
-				ranges = [][]int{{0, info.yearlen}}
+				ranges = append(ranges, [2]int{0, info.yearlen})
 			}
 		} else if info.rrule.freq == MONTHLY {
-			ranges = [][]int{info.mrange[month-1 : month+1]}
+			ranges = append(ranges, [2]int{info.mrange[month-1], info.mrange[month]})
 		}
+		info.ranges = ranges
 		if len(ranges) != 0 {
 			// Weekly frequency won't get here, so we may not
 			// care about cross-year weekly periods.
-			info.nwdaymask = make([]int, info.yearlen)
+			info.nwdaymask = zeroed(info.nwdaymask, info.yearlen)
 			for _, x := range ranges {
 				first, last := x[0], x[1]
 				last--
@@ -476,8 +538,8 @@ func (info *iterInfo) rebuild(year int, month time.Month) {
 		}
 	}
 	if len(info.rrule.byeaster) != 0 {
-		info.eastermask = make([]int, info.yearlen+7)
-		eyday := easter(year).YearDay() - 1
+		info.eastermask = zeroed(info.eastermask, info.yearlen+7)
+		eyday := easter(year, info.rrule.computus).YearDay() - 1
 		for _, offset := range info.rrule.byeaster {
 			info.eastermask[eyday+offset] = 1
 		}
@@ -486,6 +548,27 @@ func (info *iterInfo) rebuild(year int, month time.Month) {
 	info.lastmonth = month
 }
 
+// excluded reports whether the day at index i of the current year is
+// filtered out by the day-level BY* parts.
+func (info *iterInfo) excluded(i int) bool {
+	r := info.rrule
+	return len(r.bymonth) != 0 && !contains(r.bymonth, info.mmask[i]) ||
+		len(r.byweekno) != 0 && info.wnomask[i] == 0 ||
+		len(r.byweekday) != 0 && !contains(r.byweekday, info.wdaymask[i]) ||
+		len(info.nwdaymask) != 0 && info.nwdaymask[i] == 0 ||
+		len(r.byeaster) != 0 && info.eastermask[i] == 0 ||
+		(len(r.bymonthday) != 0 || len(r.bynmonthday) != 0) &&
+			!contains(r.bymonthday, info.mdaymask[i]) &&
+			!contains(r.bynmonthday, info.nmdaymask[i]) ||
+		len(r.byyearday) != 0 &&
+			(i < info.yearlen &&
+				!contains(r.byyearday, i+1) &&
+				!contains(r.byyearday, -info.yearlen+i) ||
+				i >= info.yearlen &&
+					!contains(r.byyearday, i+1-info.yearlen) &&
+					!contains(r.byyearday, -info.nextyearlen+i-info.yearlen))
+}
+
 func (info *iterInfo) calcDaySet(freq Frequency, year int, month time.Month, day int) (start, end int) {
 	switch freq {
 	case YEARLY:
@@ -526,26 +609,26 @@ func (info *iterInfo) fillTimeSet(set *[]time.Time, freq Frequency, hour, minute
 		prepareTimeSet(set, len(info.rrule.byminute)*len(info.rrule.bysecond))
 		for _, minute := range info.rrule.byminute {
 			for _, second := range info.rrule.bysecond {
-				*set = append(*set, time.Date(1, 1, 1, hour, minute, second, 0, info.rrule.dtstart.Location()))
+				*set = append(*set, time.Date(1, 1, 1, hour, minute, second, info.rrule.dtstart.Nanosecond(), info.rrule.dtstart.Location()))
 			}
 		}
-		sort.Sort(timeSlice(*set))
+		sortTimes(*set)
 	case MINUTELY:
 		prepareTimeSet(set, len(info.rrule.bysecond))
 		for _, second := range info.rrule.bysecond {
-			*set = append(*set, time.Date(1, 1, 1, hour, minute, second, 0, info.rrule.dtstart.Location()))
+			*set = append(*set, time.Date(1, 1, 1, hour, minute, second, info.rrule.dtstart.Nanosecond(), info.rrule.dtstart.Location()))
 		}
-		sort.Sort(timeSlice(*set))
+		sortTimes(*set)
 	case SECONDLY:
 		prepareTimeSet(set, 1)
-		*set = append(*set, time.Date(1, 1, 1, hour, minute, second, 0, info.rrule.dtstart.Location()))
+		*set = append(*set, time.Date(1, 1, 1, hour, minute, second, info.rrule.dtstart.Nanosecond(), info.rrule.dtstart.Location()))
 	default:
 		prepareTimeSet(set, 0)
 	}
 }
 
 func prepareTimeSet(set *[]time.Time, length int) {
-	if len(*set) < length {
+	if cap(*set) < length {
 		*set = make([]time.Time, 0, length)
 		return
 	}
@@ -555,20 +638,28 @@ func prepareTimeSet(set *[]time.Time, length int) {
 
 // rIterator is a iterator of RRule
 type rIterator struct {
-	year     int
-	month    time.Month
-	day      int
-	hour     int
-	minute   int
-	second   int
-	weekday  int
-	ii       iterInfo
-	timeset  []time.Time
-	total    int
-	count    int
-	remain   reusingRemainSlice
-	finished bool
-	dayset   []optInt
+	year    int
+	month   time.Month
+	day     int
+	hour    int
+	minute  int
+	second  int
+	weekday int
+	ii      iterInfo
+	timeset []time.Time
+	// timesetBuffer is owned by the iterator and reused as timeset by
+	// HOURLY and finer rules; coarser rules share their own timeset.
+	timesetBuffer []time.Time
+	total         int
+	count         int
+	remain        reusingRemainSlice
+	finished      bool
+	dayset        []optInt
+	// limiter, if not nil, bounds the iterations of the period loop.
+	limiter *limiter
+	// matchyear is the year of the last period with an occurrence, or of
+	// DTSTART.
+	matchyear int
 }
 
 func (iterator *rIterator) generate() {
@@ -578,6 +669,10 @@ func (iterator *rIterator) generate() {
 
 	r := iterator.ii.rrule
 	for iterator.remain.Len() == 0 {
+		if iterator.limited() {
+			return
+		}
+
 		// Get dayset with the right frequency
 		setStart, setEnd := iterator.ii.calcDaySet(r.freq, iterator.year, iterator.month, iterator.day)
 		iterator.fillDaySetMonotonic(setStart, setEnd)
@@ -587,22 +682,7 @@ func (iterator *rIterator) generate() {
 
 		// Do the "hard" work ;-)
 		for dayIndex, day := range dayset {
-			i := day.Int
-			if len(r.bymonth) != 0 && !contains(r.bymonth, iterator.ii.mmask[i]) ||
-				len(r.byweekno) != 0 && iterator.ii.wnomask[i] == 0 ||
-				len(r.byweekday) != 0 && !contains(r.byweekday, iterator.ii.wdaymask[i]) ||
-				len(iterator.ii.nwdaymask) != 0 && iterator.ii.nwdaymask[i] == 0 ||
-				len(r.byeaster) != 0 && iterator.ii.eastermask[i] == 0 ||
-				(len(r.bymonthday) != 0 || len(r.bynmonthday) != 0) &&
-					!contains(r.bymonthday, iterator.ii.mdaymask[i]) &&
-					!contains(r.bynmonthday, iterator.ii.nmdaymask[i]) ||
-				len(r.byyearday) != 0 &&
-					(i < iterator.ii.yearlen &&
-						!contains(r.byyearday, i+1) &&
-						!contains(r.byyearday, -iterator.ii.yearlen+i) ||
-						i >= iterator.ii.yearlen &&
-							!contains(r.byyearday, i+1-iterator.ii.yearlen) &&
-							!contains(r.byyearday, -iterator.ii.nextyearlen+i-iterator.ii.yearlen)) {
+			if iterator.ii.excluded(day.Int) {
 				dayset[dayIndex].Defined = false
 				filtered = true
 			}
@@ -641,16 +721,15 @@ func (iterator *rIterator) generate() {
 			sort.Sort(timeSlice(poslist))
 			for _, res := range poslist {
 				if !r.until.IsZero() && res.After(r.until) {
-					r.len = iterator.total
 					iterator.finished = true
 					return
 				} else if !res.Before(r.dtstart) {
 					iterator.total++
+					iterator.matchyear = iterator.year
 					iterator.remain.Append(res)
 					if iterator.count != 0 {
 						iterator.count--
 						if iterator.count == 0 {
-							r.len = iterator.total
 							iterator.finished = true
 							return
 						}
@@ -670,16 +749,15 @@ func (iterator *rIterator) generate() {
 						tempHour, tempMinute, tempSecond,
 						timeTemp.Nanosecond(), timeTemp.Location())
 					if !r.until.IsZero() && res.After(r.until) {
-						r.len = iterator.total
 						iterator.finished = true
 						return
 					} else if !res.Before(r.dtstart) {
 						iterator.total++
+						iterator.matchyear = iterator.year
 						iterator.remain.Append(res)
 						if iterator.count != 0 {
 							iterator.count--
 							if iterator.count == 0 {
-								r.len = iterator.total
 								iterator.finished = true
 								return
 							}
@@ -692,9 +770,7 @@ func (iterator *rIterator) generate() {
 		fixday := false
 		if r.freq == YEARLY {
 			iterator.year += r.interval
-			if iterator.year > MAXYEAR {
-				r.len = iterator.total
-				iterator.finished = true
+			if iterator.exhausted() {
 				return
 			}
 			iterator.ii.rebuild(iterator.year, iterator.month)
@@ -708,9 +784,7 @@ func (iterator *rIterator) generate() {
 					iterator.month = 12
 					iterator.year--
 				}
-				if iterator.year > MAXYEAR {
-					r.len = iterator.total
-					iterator.finished = true
+				if iterator.exhausted() {
 					return
 				}
 			}
@@ -732,6 +806,9 @@ func (iterator *rIterator) generate() {
 				iterator.hour += ((23 - iterator.hour) / r.interval) * r.interval
 			}
 			for {
+				if iterator.limited() {
+					return
+				}
 				iterator.hour += r.interval
 				div, mod := divmod(iterator.hour, 24)
 				if div != 0 {
@@ -750,6 +827,9 @@ func (iterator *rIterator) generate() {
 				iterator.minute += ((1439 - (iterator.hour*60 + iterator.minute)) / r.interval) * r.interval
 			}
 			for {
+				if iterator.limited() {
+					return
+				}
 				iterator.minute += r.interval
 				div, mod := divmod(iterator.minute, 60)
 				if div != 0 {
@@ -774,6 +854,9 @@ func (iterator *rIterator) generate() {
 				iterator.second += (((86399 - (iterator.hour*3600 + iterator.minute*60 + iterator.second)) / r.interval) * r.interval)
 			}
 			for {
+				if iterator.limited() {
+					return
+				}
 				iterator.second += r.interval
 				div, mod := divmod(iterator.second, 60)
 				if div != 0 {
@@ -808,9 +891,7 @@ func (iterator *rIterator) generate() {
 					if iterator.month == 13 {
 						iterator.month = 1
 						iterator.year++
-						if iterator.year > MAXYEAR {
-							r.len = iterator.total
-							iterator.finished = true
+						if iterator.exhausted() {
 							return
 						}
 					}
@@ -822,6 +903,37 @@ func (iterator *rIterator) generate() {
 	}
 }
 
+// exhausted finishes the iterator when the rule has had no occurrence for a
+// whole cycle of the calendar, as it then never has one again. Every period
+// of the rule recurs in the same state after 400 INTERVALs of years, the
+// cycle of the Gregorian calendar. The dates of Easter do not follow it, so
+// BYEASTER rules wait 5,600 INTERVALs of years instead. Orthodox Easter
+// slowly drifts through the Gregorian year, so that such rules may end early
+// tens of thousands of years from now.
+func (iterator *rIterator) exhausted() bool {
+	r := iterator.ii.rrule
+	cycle := gregorianCycle
+	if len(r.byeaster) != 0 {
+		cycle = easterGap
+	}
+	if iterator.year-iterator.matchyear > cycle*r.interval {
+		iterator.finished = true
+	}
+	return iterator.finished
+}
+
+// limited counts an iteration against the limiter, if any, and finishes the
+// iterator when a limit is exceeded. The loops stepping through hours,
+// minutes or seconds count too, as they never end when BYHOUR, BYMINUTE or
+// BYSECOND cannot be reached with the INTERVAL.
+func (iterator *rIterator) limited() bool {
+	if iterator.limiter != nil && !iterator.limiter.iterate() {
+		iterator.finished = true
+		return true
+	}
+	return false
+}
+
 func (iterator *rIterator) fillDaySetMonotonic(start, end int) {
 	desiredLen := end - start
 
@@ -876,27 +988,56 @@ func (s *reusingRemainSlice) Pop() (ret time.Time, ok bool) {
 
 // Iterator return an iterator for RRule
 func (r *RRule) Iterator() Next {
-	iterator := rIterator{}
+	if r.cache != nil {
+		return r.cache.iterator()
+	}
+	return r.iterator()
+}
+
+func (r *RRule) iterator() Next {
+	iterator := &rIterator{}
+	iterator.reset(r)
+	return iterator.next
+}
+
+// reset starts iterating over r, keeping the buffers of the iterator.
+func (iterator *rIterator) reset(r *RRule) {
+	// The timeset of rules below HOURLY belongs to the rule.
+	buffer := iterator.timesetBuffer
+	if iterator.ii.rrule != nil && iterator.ii.rrule.freq >= HOURLY {
+		buffer = iterator.timeset
+	}
+	*iterator = rIterator{
+		ii: iterInfo{
+			rrule:      r,
+			wnomask:    iterator.ii.wnomask[:0],
+			nwdaymask:  iterator.ii.nwdaymask[:0],
+			eastermask: iterator.ii.eastermask[:0],
+			ranges:     iterator.ii.ranges[:0],
+		},
+		timesetBuffer: buffer[:0],
+		remain:        reusingRemainSlice{storage: iterator.remain.backup[:0], backup: iterator.remain.backup[:0]},
+		dayset:        iterator.dayset[:0],
+	}
 	iterator.year, iterator.month, iterator.day = r.dtstart.Date()
+	iterator.matchyear = iterator.year
 	iterator.hour, iterator.minute, iterator.second = r.dtstart.Clock()
 	iterator.weekday = toPyWeekday(r.dtstart.Weekday())
-
-	iterator.ii = iterInfo{rrule: r}
 	iterator.ii.rebuild(iterator.year, iterator.month)
 
 	if r.freq < HOURLY {
 		iterator.timeset = r.timeset
 	} else {
+		iterator.timeset = iterator.timesetBuffer
 		if r.freq >= HOURLY && len(r.byhour) != 0 && !contains(r.byhour, iterator.hour) ||
 			r.freq >= MINUTELY && len(r.byminute) != 0 && !contains(r.byminute, iterator.minute) ||
 			r.freq >= SECONDLY && len(r.bysecond) != 0 && !contains(r.bysecond, iterator.second) {
-			iterator.timeset = nil
+			iterator.timeset = iterator.timeset[:0]
 		} else {
 			iterator.ii.fillTimeSet(&iterator.timeset, r.freq, iterator.hour, iterator.minute, iterator.second)
 		}
 	}
 	iterator.count = r.count
-	return iterator.next
 }
 
 // All returns all occurrences of the RRule.
@@ -929,8 +1070,16 @@ func (r *RRule) After(dt time.Time, inc bool) time.Time {
 
 // DTStart set a new DTSTART for the rule and recalculates the timeset if needed.
 func (r *RRule) DTStart(dt time.Time) {
-	r.OrigOptions.Dtstart = dt.Truncate(time.Second)
-	*r = buildRRule(r.OrigOptions)
+	r.OrigOptions.Dtstart = r.OrigOptions.truncate(dt)
+	r.rebuild()
+}
+
+// WithDTStart returns a copy of the rule with a new DTSTART, leaving r
+// unchanged.
+func (r *RRule) WithDTStart(dt time.Time) *RRule {
+	option := r.OrigOptions
+	option.Dtstart = option.truncate(dt)
+	return r.with(option)
 }
 
 // GetDTStart gets DTSTART time for rrule
@@ -940,11 +1089,37 @@ func (r *RRule) GetDTStart() time.Time {
 
 // Until set a new UNTIL for the rule and recalculates the timeset if needed.
 func (r *RRule) Until(ut time.Time) {
-	r.OrigOptions.Until = ut.Truncate(time.Second)
-	*r = buildRRule(r.OrigOptions)
+	r.OrigOptions.Until = r.OrigOptions.truncate(ut)
+	r.rebuild()
+}
+
+// WithUntil returns a copy of the rule with a new UNTIL, leaving r
+// unchanged.
+func (r *RRule) WithUntil(ut time.Time) *RRule {
+	option := r.OrigOptions
+	option.Until = option.truncate(ut)
+	return r.with(option)
 }
 
 // GetUntil gets UNTIL time for rrule
 func (r *RRule) GetUntil() time.Time {
 	return r.until
 }
+
+// rebuild applies changes to OrigOptions, keeping the cache empty.
+func (r *RRule) rebuild() {
+	cache := r.cache
+	*r = buildRRule(r.OrigOptions)
+	r.cache = cache
+	cache.reset()
+}
+
+// with returns a new rule built from option, with an empty cache if r has
+// one.
+func (r *RRule) with(option ROption) *RRule {
+	nr := buildRRule(option)
+	if r.cache != nil {
+		nr.cache = newOccurrenceCache(r.cache.opts, nr.iterator)
+	}
+	return &nr
+}
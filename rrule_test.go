@@ -1,6 +1,7 @@
 package rrule
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -593,6 +594,67 @@ func TestYearlyByEasterPos(t *testing.T) {
 	}
 }
 
+func TestYearlyByOrthodoxEaster(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: YEARLY,
+		Count:    4,
+		Byeaster: []int{0},
+		Computus: Orthodox,
+		Dtstart:  time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(2020, 4, 19, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 5, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2022, 4, 24, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 4, 16, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestUnknownComputus(t *testing.T) {
+	_, err := NewRRule(ROption{Freq: YEARLY, Byeaster: []int{0}, Computus: Computus(2)})
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Kind != ErrOutOfRange || perr.Param != "COMPUTUS" {
+		t.Errorf("get %v, want a COMPUTUS ErrOutOfRange", err)
+	}
+}
+
+func TestEasterCycle(t *testing.T) {
+	// The dates of Western Easter repeat every 5,700,000 years.
+	for year := -800; year <= 800; year++ {
+		e, later := easter(year, Western), easter(year+5700000, Western)
+		if e.Month() != later.Month() || e.Day() != later.Day() {
+			t.Errorf("easter(%d) = %v, easter(%d) = %v", year, e, year+5700000, later)
+		}
+	}
+}
+
+func TestProlepticYears(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: YEARLY,
+		Count:      3,
+		Bymonth:    []int{2},
+		Bymonthday: []int{-1},
+		Dtstart:    time.Date(-1, 1, 1, 9, 0, 0, 0, time.UTC)})
+	want := []time.Time{time.Date(-1, 2, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(0, 2, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(1, 2, 28, 9, 0, 0, 0, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+
+	r, _ = NewRRule(ROption{Freq: MONTHLY,
+		Bymonthday: []int{31},
+		Dtstart:    time.Date(MAXYEAR, 11, 1, 9, 0, 0, 0, time.UTC),
+		Until:      time.Date(MAXYEAR+1, 3, 31, 9, 0, 0, 0, time.UTC)})
+	want = []time.Time{time.Date(MAXYEAR, 12, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(MAXYEAR+1, 1, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(MAXYEAR+1, 3, 31, 9, 0, 0, 0, time.UTC)}
+	value = r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
 func TestYearlyByEasterNeg(t *testing.T) {
 	r, _ := NewRRule(ROption{Freq: YEARLY,
 		Count:    3,
@@ -3746,6 +3808,29 @@ func TestDTStartWithMicroseconds(t *testing.T) {
 	}
 }
 
+func TestDTStartSubsecond(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 3, Subsecond: true,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 250000000, time.UTC)})
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 250000000, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 250000000, time.UTC),
+		time.Date(1997, 9, 4, 9, 0, 0, 250000000, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestSecondlySubsecond(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: SECONDLY, Count: 2, Subsecond: true,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 500, time.UTC),
+		Until:   time.Date(1997, 9, 2, 9, 0, 1, 499, time.UTC)})
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 500, time.UTC)}
+	value := r.All()
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
 func TestUntil(t *testing.T) {
 	r1, _ := NewRRule(ROption{Freq: DAILY,
 		Dtstart: time.Date(1997, 9, 2, 0, 0, 0, 0, time.UTC)})
@@ -3765,8 +3850,8 @@ func TestUntil(t *testing.T) {
 		Dtstart: time.Date(MAXYEAR-100, 1, 1, 0, 0, 0, 0, time.UTC)})
 	r3.Until(time.Date(MAXYEAR+100, 1, 1, 0, 0, 0, 0, time.UTC))
 	v3 := r3.All()
-	if len(v3) != 101*12 {
-		t.Errorf("get %v, want %v", len(v3), 101*12)
+	if len(v3) != 200*12+1 {
+		t.Errorf("get %v, want %v", len(v3), 200*12+1)
 	}
 }
 
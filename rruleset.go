@@ -1,17 +1,21 @@
 package rrule
 
 import (
+	"container/heap"
 	"fmt"
-	"sort"
 	"time"
 )
 
 // Set allows more complex recurrence setups, mixing multiple rules, dates, exclusion rules, and exclusion dates
+// Iterating does not modify a Set, so it can be shared between goroutines as
+// long as it is not changed; the With* methods return changed copies instead.
 type Set struct {
-	dtstart time.Time
-	rrule   *RRule
-	rdate   []time.Time
-	exdate  []time.Time
+	dtstart   time.Time
+	rrule     *RRule
+	rdate     []time.Time
+	exdate    []time.Time
+	precision time.Duration
+	cache     *occurrenceCache
 }
 
 // Recurrence returns a slice of all the recurrence rules for a set
@@ -37,13 +41,57 @@ func (set *Set) Recurrence() []string {
 	return res
 }
 
+// Precision sets the precision at which DTSTART, RDATE and EXDATE values are
+// kept and EXDATEs are matched against occurrences. It defaults to
+// time.Second; use time.Nanosecond for an exact match, e.g. together with
+// ROption.Subsecond. It should be set before adding any date.
+func (set *Set) Precision(d time.Duration) {
+	set.precision = d
+	set.cache.reset()
+}
+
+// GetPrecision gets the precision of dates in the set.
+func (set *Set) GetPrecision() time.Duration {
+	if set.precision <= 0 {
+		return time.Second
+	}
+	return set.precision
+}
+
+func (set *Set) truncate(t time.Time) time.Time {
+	return t.Truncate(set.GetPrecision())
+}
+
 // DTStart sets dtstart property for set
 func (set *Set) DTStart(dtstart time.Time) {
-	set.dtstart = dtstart.Truncate(time.Second)
+	set.dtstart = set.truncate(dtstart)
 
 	if set.rrule != nil {
-		set.rrule.DTStart(set.dtstart)
+		// The rule may be shared with copies of the set.
+		set.rrule = set.rrule.WithDTStart(set.dtstart)
+	}
+	set.cache.reset()
+}
+
+// WithDTStart returns a copy of the set with a new DTSTART, leaving set
+// unchanged.
+func (set *Set) WithDTStart(dtstart time.Time) *Set {
+	c := set.clone()
+	c.dtstart = c.truncate(dtstart)
+	if c.rrule != nil {
+		c.rrule = c.rrule.WithDTStart(c.dtstart)
+	}
+	return c
+}
+
+// WithUntil returns a copy of the set with a new UNTIL for its RRULE,
+// leaving set unchanged.
+func (set *Set) WithUntil(until time.Time) *Set {
+	c := set.clone()
+	if c.rrule != nil {
+		c.rrule = c.rrule.WithUntil(until)
 	}
+	return c
 }
 
 // GetDTStart gets DTSTART for set
@@ -60,6 +108,7 @@ func (set *Set) RRule(rrule *RRule) {
 		rrule.DTStart(set.dtstart)
 	}
 	set.rrule = rrule
+	set.cache.reset()
 }
 
 // GetRRule returns the rrules in the set
@@ -69,15 +118,27 @@ func (set *Set) GetRRule() *RRule {
 
 // RDate include the given datetime instance in the recurrence set generation.
 func (set *Set) RDate(rdate time.Time) {
-	set.rdate = append(set.rdate, rdate.Truncate(time.Second))
+	set.rdate = append(set.rdate, set.truncate(rdate))
+	set.cache.reset()
 }
 
 // SetRDates sets explicitly added dates (rdates) in the set
 func (set *Set) SetRDates(rdates []time.Time) {
 	set.rdate = make([]time.Time, 0, len(rdates))
 	for _, rdate := range rdates {
-		set.rdate = append(set.rdate, rdate.Truncate(time.Second))
+		set.rdate = append(set.rdate, set.truncate(rdate))
+	}
+	set.cache.reset()
+}
+
+// WithRDate returns a copy of the set including the given dates, leaving
+// set unchanged.
+func (set *Set) WithRDate(rdates ...time.Time) *Set {
+	c := set.clone()
+	for _, rdate := range rdates {
+		c.rdate = append(c.rdate, c.truncate(rdate))
 	}
+	return c
 }
 
 // GetRDate returns explicitly added dates (rdates) in the set
@@ -89,15 +150,43 @@ func (set *Set) GetRDate() []time.Time {
 // Dates included that way will not be generated,
 // even if some inclusive rrule or rdate matches them.
 func (set *Set) ExDate(exdate time.Time) {
-	set.exdate = append(set.exdate, exdate.Truncate(time.Second))
+	set.exdate = append(set.exdate, set.truncate(exdate))
+	set.cache.reset()
 }
 
 // SetExDates sets explicitly excluded dates (exdates) in the set
 func (set *Set) SetExDates(exdates []time.Time) {
 	set.exdate = make([]time.Time, 0, len(exdates))
 	for _, exdate := range exdates {
-		set.exdate = append(set.exdate, exdate.Truncate(time.Second))
+		set.exdate = append(set.exdate, set.truncate(exdate))
+	}
+	set.cache.reset()
+}
+
+// WithExDate returns a copy of the set excluding the given dates, leaving
+// set unchanged.
+func (set *Set) WithExDate(exdates ...time.Time) *Set {
+	c := set.clone()
+	for _, exdate := range exdates {
+		c.exdate = append(c.exdate, c.truncate(exdate))
+	}
+	return c
+}
+
+// clone returns a copy of the set that can be changed without affecting
+// set. The RRULE is shared, and must be replaced rather than changed.
+func (set *Set) clone() *Set {
+	c := &Set{
+		dtstart:   set.dtstart,
+		rrule:     set.rrule,
+		rdate:     append([]time.Time(nil), set.rdate...),
+		exdate:    append([]time.Time(nil), set.exdate...),
+		precision: set.precision,
 	}
+	if set.cache != nil {
+		c.cache = newOccurrenceCache(set.cache.opts, c.iterator)
+	}
+	return c
 }
 
 // GetExDate returns explicitly excluded dates (exdates) in the set
@@ -106,64 +195,122 @@ func (set *Set) GetExDate() []time.Time {
 }
 
 type genItem struct {
-	dt  time.Time
-	gen Next
+	dt     time.Time
+	gen    Next
+	source int // index of gen in the arguments of newGenHeap
+}
+
+// genHeap merges generators of sorted times. It is a min-heap on the next
+// time of each generator, so that producing a time costs O(log k) for k
+// generators. Equal times come out in the order of their generators.
+type genHeap []genItem
+
+func (h genHeap) Len() int { return len(h) }
+func (h genHeap) Less(i, j int) bool {
+	if h[i].dt.Equal(h[j].dt) {
+		return h[i].source < h[j].source
+	}
+	return h[i].dt.Before(h[j].dt)
+}
+func (h genHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *genHeap) Push(x interface{}) { *h = append(*h, x.(genItem)) }
+func (h *genHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
 }
 
-type genItemSlice []genItem
+func newGenHeap(gens ...Next) *genHeap {
+	h := make(genHeap, 0, len(gens))
+	for i, gen := range gens {
+		if dt, ok := gen(); ok {
+			h = append(h, genItem{dt, gen, i})
+		}
+	}
+	heap.Init(&h)
+	return &h
+}
+
+// peek returns the smallest time without consuming it.
+func (h *genHeap) peek() (time.Time, bool) {
+	if len(*h) == 0 {
+		return time.Time{}, false
+	}
+	return (*h)[0].dt, true
+}
 
-func (s genItemSlice) Len() int           { return len(s) }
-func (s genItemSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
-func (s genItemSlice) Less(i, j int) bool { return s[i].dt.Before(s[j].dt) }
+// next consumes and returns the smallest time.
+func (h *genHeap) next() (time.Time, bool) {
+	dt, _, ok := h.nextSource()
+	return dt, ok
+}
 
-func addGenList(genList *[]genItem, next Next) {
-	dt, ok := next()
-	if ok {
-		*genList = append(*genList, genItem{dt, next})
+// nextSource is same as next, and also returns the index of the generator
+// that produced the time.
+func (h *genHeap) nextSource() (time.Time, int, bool) {
+	if len(*h) == 0 {
+		return time.Time{}, 0, false
+	}
+	top := &(*h)[0]
+	dt, source := top.dt, top.source
+	var ok bool
+	if top.dt, ok = top.gen(); ok {
+		heap.Fix(h, 0)
+	} else {
+		heap.Pop(h)
 	}
+	return dt, source, true
 }
 
 // Iterator returns an iterator for rrule.Set
 func (set *Set) Iterator() (next func() (time.Time, bool)) {
-	rlist := []genItem{}
-	exlist := []genItem{}
+	if set.cache != nil {
+		return set.cache.iterator()
+	}
+	return set.iterator()
+}
 
-	sort.Sort(timeSlice(set.rdate))
-	addGenList(&rlist, timeSliceIterator(set.rdate))
-	if set.rrule != nil {
-		addGenList(&rlist, set.rrule.Iterator())
+func (set *Set) iterator() Next {
+	if set.rrule == nil {
+		return set.merge(nil)
 	}
-	sort.Sort(genItemSlice(rlist))
+	return set.merge(set.rrule.Iterator())
+}
 
-	sort.Sort(timeSlice(set.exdate))
-	addGenList(&exlist, timeSliceIterator(set.exdate))
-	sort.Sort(genItemSlice(exlist))
+// merge returns the RDATEs and the occurrences of rrule, which may be nil,
+// without the EXDATEs.
+func (set *Set) merge(rrule Next) Next {
+	gens := []Next{timeSliceIterator(sortedTimes(set.rdate))}
+	if rrule != nil {
+		gens = append(gens, rrule)
+	}
+	rlist := newGenHeap(gens...)
+	exlist := newGenHeap(timeSliceIterator(sortedTimes(set.exdate)))
 
+	precision := set.GetPrecision()
 	lastdt := time.Time{}
 	return func() (time.Time, bool) {
-		for len(rlist) != 0 {
-			dt := rlist[0].dt
-			var ok bool
-			rlist[0].dt, ok = rlist[0].gen()
+		for {
+			dt, ok := rlist.next()
 			if !ok {
-				rlist = rlist[1:]
+				return time.Time{}, false
 			}
-			sort.Sort(genItemSlice(rlist))
-			if lastdt.IsZero() || !lastdt.Equal(dt) {
-				for len(exlist) != 0 && exlist[0].dt.Before(dt) {
-					exlist[0].dt, ok = exlist[0].gen()
-					if !ok {
-						exlist = exlist[1:]
-					}
-					sort.Sort(genItemSlice(exlist))
-				}
-				lastdt = dt
-				if len(exlist) == 0 || !dt.Equal(exlist[0].dt) {
-					return dt, true
+			if !lastdt.IsZero() && lastdt.Equal(dt) {
+				continue
+			}
+			lastdt = dt
+			for {
+				ex, ok := exlist.peek()
+				if !ok || !ex.Truncate(precision).Before(dt.Truncate(precision)) {
+					break
 				}
+				exlist.next()
+			}
+			if ex, ok := exlist.peek(); !ok || !dt.Truncate(precision).Equal(ex.Truncate(precision)) {
+				return dt, true
 			}
 		}
-		return time.Time{}, false
 	}
 }
 
@@ -1,6 +1,8 @@
 package rrule
 
 import (
+	"fmt"
+	"sort"
 	"testing"
 	"time"
 )
@@ -191,6 +193,33 @@ func TestSetExDateRevOrder(t *testing.T) {
 	}
 }
 
+func TestSetExDatePrecision(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 3, Subsecond: true,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 250000000, time.UTC)})
+
+	set := Set{}
+	set.RRule(r)
+	set.ExDate(time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC))
+	value := set.All()
+	want := []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 250000000, time.UTC),
+		time.Date(1997, 9, 4, 9, 0, 0, 250000000, time.UTC)}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+
+	set = Set{}
+	set.Precision(time.Nanosecond)
+	set.RRule(r)
+	set.ExDate(time.Date(1997, 9, 3, 9, 0, 0, 0, time.UTC))
+	set.ExDate(time.Date(1997, 9, 4, 9, 0, 0, 250000000, time.UTC))
+	value = set.All()
+	want = []time.Time{time.Date(1997, 9, 2, 9, 0, 0, 250000000, time.UTC),
+		time.Date(1997, 9, 3, 9, 0, 0, 250000000, time.UTC)}
+	if !timesEqual(value, want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
 func TestSetDateAndExDate(t *testing.T) {
 	set := Set{}
 	set.RDate(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC))
@@ -375,3 +404,151 @@ func TestRuleSetChangeDTStartTimezoneRespected(t *testing.T) {
 		}
 	}
 }
+
+func TestSetConcurrentIteration(t *testing.T) {
+	set, err := StrToRRuleSet("DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY;COUNT=10\n" +
+		"RDATE:20200301T090000Z,20200201T090000Z\nEXDATE:20200105T090000Z,20200103T090000Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{
+		time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 4, 9, 0, 0, 0, time.UTC),
+	}
+	after, before := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)
+	done := make(chan bool)
+	for i := 0; i < 4; i++ {
+		go func() {
+			got := set.Between(after, before, true)
+			done <- timesEqual(got, want)
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		if !<-done {
+			t.Error("concurrent Between returned wrong occurrences")
+		}
+	}
+	if rdate := set.GetRDate(); !rdate[0].After(rdate[1]) {
+		t.Errorf("get %v, want RDATE kept in insertion order", rdate)
+	}
+}
+
+func TestSetWith(t *testing.T) {
+	set, err := StrToRRuleSet("DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY;COUNT=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := set.String()
+
+	moved := set.WithDTStart(time.Date(2020, 2, 1, 9, 0, 0, 0, time.UTC)).
+		WithUntil(time.Date(2020, 2, 2, 9, 0, 0, 0, time.UTC)).
+		WithExDate(time.Date(2020, 2, 2, 9, 0, 0, 0, time.UTC)).
+		WithRDate(time.Date(2020, 3, 1, 9, 0, 0, 0, time.UTC))
+	want := []time.Time{
+		time.Date(2020, 2, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+	if got := moved.All(); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+	if got := set.String(); got != orig {
+		t.Errorf("get %v, want unchanged %v", got, orig)
+	}
+
+	r := set.GetRRule()
+	if r2 := r.WithUntil(time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC)); len(r2.All()) != 2 || len(r.All()) != 3 {
+		t.Errorf("get %v and %v, want 2 and 3 occurrences", r2.All(), r.All())
+	}
+
+	c := set.WithExDate(time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC))
+	c.DTStart(time.Date(2021, 1, 1, 9, 0, 0, 0, time.UTC))
+	want = []time.Time{
+		time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 3, 9, 0, 0, 0, time.UTC),
+	}
+	if got := set.All(); !timesEqual(got, want) {
+		t.Errorf("get %v, want unchanged %v", got, want)
+	}
+}
+
+func BenchmarkSetIterator(b *testing.B) {
+	dtstart := time.Date(2000, 03, 22, 12, 0, 0, 0, time.UTC)
+	newSet := func(rdates, exdates int) *Set {
+		set := &Set{}
+		r, err := NewRRule(ROption{Dtstart: dtstart, Freq: DAILY})
+		if err != nil {
+			b.Fatalf("failed to init rrule: %s", err)
+		}
+		set.RRule(r)
+		for i := 0; i < rdates; i++ {
+			set.RDate(dtstart.Add(time.Duration(i)*time.Hour + 30*time.Minute))
+		}
+		for i := 0; i < exdates; i++ {
+			set.ExDate(dtstart.AddDate(0, 0, 2*i+1))
+		}
+		return set
+	}
+
+	for _, c := range []struct {
+		Name string
+		Set  *Set
+	}{
+		{"rrule only", newSet(0, 0)},
+		{"thousands of rdates", newSet(5000, 0)},
+		{"thousands of exdates", newSet(0, 5000)},
+	} {
+		c := c
+		b.Run(c.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				res := iterateNum(c.Set.Iterator(), 2000)
+				if res.IsZero() {
+					b.Error("expected not zero iterator result")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGenHeap(b *testing.B) {
+	dtstart := time.Date(2000, 03, 22, 12, 0, 0, 0, time.UTC)
+	for _, rules := range []int{1, 10, 100} {
+		rrules := make([]*RRule, rules)
+		for i := range rrules {
+			var err error
+			rrules[i], err = NewRRule(ROption{Dtstart: dtstart.Add(time.Duration(i) * time.Minute), Freq: HOURLY})
+			if err != nil {
+				b.Fatalf("failed to init rrule: %s", err)
+			}
+		}
+		b.Run(fmt.Sprintf("%d rules", rules), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				gens := make([]Next, len(rrules))
+				for j, r := range rrules {
+					gens[j] = r.Iterator()
+				}
+				h := newGenHeap(gens...)
+				res := iterateNum(h.next, 2000)
+				if res.IsZero() {
+					b.Error("expected not zero iterator result")
+				}
+			}
+		})
+	}
+}
+
+func TestGenHeap(t *testing.T) {
+	dtstart := time.Date(2000, 03, 22, 12, 0, 0, 0, time.UTC)
+	var gens []Next
+	var want []time.Time
+	for i, freq := range []Frequency{DAILY, HOURLY, WEEKLY} {
+		r, _ := NewRRule(ROption{Dtstart: dtstart.Add(time.Duration(i) * time.Minute), Freq: freq, Count: 50})
+		gens = append(gens, r.Iterator())
+		want = append(want, r.All()...)
+	}
+	sort.Sort(timeSlice(want))
+	if got := all(newGenHeap(gens...).next); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,346 @@
+// Package scheduler fires callbacks or channel sends at each occurrence of
+// recurrence rules.
+//
+// A Scheduler sleeps until the next occurrence of every registered job,
+// using Source.After to find it, and takes care of clock injection, jitter,
+// cancellation and occurrences missed while the process was suspended.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Source yields the occurrences of a job. Both *rrule.RRule and *rrule.Set
+// implement it.
+type Source interface {
+	After(dt time.Time, inc bool) time.Time
+}
+
+// Clock tells the time and creates timers. It can be replaced in tests.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the subset of *time.Timer used by the scheduler.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+type realTimer struct{ t *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.t.C }
+
+func (t realTimer) Stop() bool { return t.t.Stop() }
+
+// MissedPolicy decides what happens to occurrences whose time has passed by
+// more than the scheduler's tolerance when it wakes up, e.g. after the
+// machine was suspended.
+type MissedPolicy int
+
+// Missed-run policies
+const (
+	// Skip drops missed occurrences.
+	Skip MissedPolicy = iota
+	// CatchUp fires every missed occurrence, in order.
+	CatchUp
+	// RunOnce fires the latest missed occurrence only.
+	RunOnce
+)
+
+// ID identifies a job added to a Scheduler.
+type ID int
+
+// Fire describes one firing of a job.
+type Fire struct {
+	ID ID
+	// Time is the occurrence, without jitter.
+	Time time.Time
+	// Missed is true when the occurrence was fired late because of the
+	// CatchUp or RunOnce policies.
+	Missed bool
+}
+
+// Job is a recurring task. At least one of Func and C must be set.
+//
+// The firings of a job run one at a time and in order, so that a slow Func
+// or receiver delays the next firings of its job, but not other jobs.
+type Job struct {
+	Source Source
+	// Func is called at each occurrence, outside the scheduler's goroutine.
+	Func func(ctx context.Context, f Fire)
+	// C receives a Fire at each occurrence. Sends block until received or
+	// the scheduler stops.
+	C chan<- Fire
+	// Jitter delays each firing by a random duration in [0, Jitter).
+	Jitter time.Duration
+	// Missed is the policy for occurrences missed by more than the
+	// scheduler's tolerance.
+	Missed MissedPolicy
+}
+
+// ErrNoOccurrence is returned by Add when the job has no future occurrence.
+var ErrNoOccurrence = errors.New("job has no future occurrence")
+
+type entry struct {
+	job  Job
+	next time.Time // next occurrence
+	fire time.Time // next occurrence plus jitter
+	// queue holds the firings due but not yet run, and running is true
+	// while a goroutine runs them.
+	queue   []Fire
+	running bool
+}
+
+// Scheduler runs jobs at the occurrences of their sources.
+// Jobs can be added and removed at any time, including while Run is active.
+type Scheduler struct {
+	clock     Clock
+	tolerance time.Duration
+
+	mu     sync.Mutex
+	rand   *rand.Rand
+	lastID ID
+	jobs   map[ID]*entry
+	wake   chan struct{}
+}
+
+// Option configures a Scheduler.
+type Option func(*Scheduler)
+
+// WithClock replaces the wall clock, typically with a fake one in tests.
+func WithClock(clock Clock) Option {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// WithTolerance sets how late an occurrence may fire before it is treated as
+// missed. It defaults to one second.
+func WithTolerance(d time.Duration) Option {
+	return func(s *Scheduler) {
+		s.tolerance = d
+	}
+}
+
+// New returns a Scheduler without jobs.
+func New(opts ...Option) *Scheduler {
+	s := &Scheduler{
+		clock:     realClock{},
+		tolerance: time.Second,
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		jobs:      map[ID]*entry{},
+		wake:      make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Add registers a job and returns its ID. The job's first occurrence is the
+// first one at or after the current time.
+func (s *Scheduler) Add(job Job) (ID, error) {
+	if job.Source == nil || job.Func == nil && job.C == nil {
+		return 0, errors.New("job needs a Source and a Func or C")
+	}
+	next := job.Source.After(s.clock.Now(), true)
+	if next.IsZero() {
+		return 0, ErrNoOccurrence
+	}
+
+	s.mu.Lock()
+	s.lastID++
+	id := s.lastID
+	s.jobs[id] = &entry{job: job, next: next, fire: s.jitter(next, job.Jitter)}
+	s.mu.Unlock()
+	s.notify()
+	return id, nil
+}
+
+// Remove unregisters a job. Firings already in flight are not interrupted,
+// those still queued behind them are dropped.
+func (s *Scheduler) Remove(id ID) {
+	s.mu.Lock()
+	if e, ok := s.jobs[id]; ok {
+		e.queue = nil
+	}
+	delete(s.jobs, id)
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Len returns the number of registered jobs. Jobs are dropped once their
+// source has no more occurrences.
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.jobs)
+}
+
+// Run fires jobs until ctx is done, then waits for running callbacks to
+// return and returns ctx.Err().
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		var timer Timer
+		var timerC <-chan time.Time
+		if d, ok := s.untilNext(); ok {
+			timer = s.clock.NewTimer(d)
+			timerC = timer.C()
+		}
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return ctx.Err()
+		case <-s.wake:
+			if timer != nil {
+				timer.Stop()
+			}
+		case <-timerC:
+			for _, e := range s.due() {
+				wg.Add(1)
+				go func(e *entry) {
+					defer wg.Done()
+					s.run(ctx, e)
+				}(e)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// jitter returns t delayed by a random duration in [0, max).
+// It must be called with s.mu held.
+func (s *Scheduler) jitter(t time.Time, max time.Duration) time.Time {
+	if max <= 0 {
+		return t
+	}
+	return t.Add(time.Duration(s.rand.Int63n(int64(max))))
+}
+
+// untilNext returns the time left until the earliest firing.
+func (s *Scheduler) untilNext() (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var earliest time.Time
+	for _, e := range s.jobs {
+		if earliest.IsZero() || e.fire.Before(earliest) {
+			earliest = e.fire
+		}
+	}
+	if earliest.IsZero() {
+		return 0, false
+	}
+	d := earliest.Sub(s.clock.Now())
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+// run runs the queued firings of e until its queue is empty or ctx is done.
+func (s *Scheduler) run(ctx context.Context, e *entry) {
+	for {
+		s.mu.Lock()
+		if len(e.queue) == 0 || ctx.Err() != nil {
+			e.running = false
+			s.mu.Unlock()
+			return
+		}
+		fire := e.queue[0]
+		e.queue = e.queue[1:]
+		s.mu.Unlock()
+
+		if e.job.Func != nil {
+			e.job.Func(ctx, fire)
+		}
+		if e.job.C != nil {
+			select {
+			case e.job.C <- fire:
+			case <-ctx.Done():
+			}
+		}
+	}
+}
+
+// due queues the firings whose time has come according to each job's
+// missed-run policy, and advances the jobs past the current time. It returns
+// the jobs whose queue needs a goroutine to run it.
+func (s *Scheduler) due() []*entry {
+	now := s.clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []*entry
+	for id, e := range s.jobs {
+		if e.fire.After(now) {
+			continue
+		}
+		occurrences := []time.Time{e.next}
+		for {
+			next := e.job.Source.After(occurrences[len(occurrences)-1], false)
+			if next.IsZero() || next.After(now) {
+				break
+			}
+			occurrences = append(occurrences, next)
+		}
+		// The policy only applies to the occurrences late by more than the
+		// tolerance; the others fire normally.
+		missed := 0
+		for i, t := range occurrences {
+			if i == 0 {
+				t = e.fire
+			}
+			if now.Sub(t) <= s.tolerance {
+				break
+			}
+			missed++
+		}
+		switch e.job.Missed {
+		case CatchUp:
+			for _, t := range occurrences[:missed] {
+				e.queue = append(e.queue, Fire{ID: id, Time: t, Missed: true})
+			}
+		case RunOnce:
+			if missed != 0 {
+				e.queue = append(e.queue, Fire{ID: id, Time: occurrences[missed-1], Missed: true})
+			}
+		}
+		for _, t := range occurrences[missed:] {
+			e.queue = append(e.queue, Fire{ID: id, Time: t})
+		}
+		if len(e.queue) != 0 && !e.running {
+			e.running = true
+			result = append(result, e)
+		}
+
+		next := e.job.Source.After(now, false)
+		if next.IsZero() {
+			delete(s.jobs, id)
+			continue
+		}
+		e.next, e.fire = next, s.jitter(next, e.job.Jitter)
+	}
+	return result
+}
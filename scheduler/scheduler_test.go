@@ -0,0 +1,343 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.c <- c.now
+		return t
+	}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Set moves the clock to now once a timer is pending, and fires the timers
+// that expired.
+func (c *fakeClock) Set(now time.Time) {
+	for {
+		c.mu.Lock()
+		if len(c.timers) != 0 {
+			break
+		}
+		c.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	defer c.mu.Unlock()
+	c.now = now
+	kept := c.timers[:0]
+	for _, t := range c.timers {
+		if t.deadline.After(now) {
+			kept = append(kept, t)
+		} else {
+			t.c <- now
+		}
+	}
+	c.timers = kept
+}
+
+// Next moves the clock to the earliest pending timer and returns the new time.
+func (c *fakeClock) Next() time.Time {
+	for {
+		c.mu.Lock()
+		if len(c.timers) != 0 {
+			break
+		}
+		c.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	next := c.timers[0].deadline
+	for _, t := range c.timers {
+		if t.deadline.Before(next) {
+			next = t.deadline
+		}
+	}
+	c.mu.Unlock()
+	c.Set(next)
+	return next
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, other := range t.clock.timers {
+		if other == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func hourly(t *testing.T, count int) *rrule.RRule {
+	r, err := rrule.NewRRule(rrule.ROption{Freq: rrule.HOURLY, Count: count,
+		Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func receive(t *testing.T, c <-chan Fire) Fire {
+	t.Helper()
+	select {
+	case f := <-c:
+		return f
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a fire")
+		return Fire{}
+	}
+}
+
+func start(s *Scheduler) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestSchedulerFires(t *testing.T) {
+	clock := &fakeClock{now: time.Date(1997, 9, 2, 8, 30, 0, 0, time.UTC)}
+	s := New(WithClock(clock))
+	c := make(chan Fire)
+	id, err := s.Add(Job{Source: hourly(t, 3), C: c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := start(s)
+	defer stop()
+
+	for _, want := range []time.Time{
+		time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 2, 10, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 2, 11, 0, 0, 0, time.UTC),
+	} {
+		clock.Set(want)
+		f := receive(t, c)
+		if f.ID != id || !f.Time.Equal(want) || f.Missed {
+			t.Errorf("get %+v, want %v", f, want)
+		}
+	}
+	if s.Len() != 0 {
+		t.Errorf("get %d jobs, want 0 once the rule is exhausted", s.Len())
+	}
+}
+
+func TestSchedulerMissed(t *testing.T) {
+	cases := []struct {
+		policy MissedPolicy
+		want   []time.Time
+	}{
+		{Skip, []time.Time{
+			time.Date(1997, 9, 2, 12, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 2, 13, 0, 0, 0, time.UTC),
+		}},
+		{CatchUp, []time.Time{
+			time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 2, 10, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 2, 11, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 2, 12, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 2, 13, 0, 0, 0, time.UTC),
+		}},
+		{RunOnce, []time.Time{
+			time.Date(1997, 9, 2, 11, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 2, 12, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 2, 13, 0, 0, 0, time.UTC),
+		}},
+	}
+	for _, c := range cases {
+		clock := &fakeClock{now: time.Date(1997, 9, 2, 8, 30, 0, 0, time.UTC)}
+		s := New(WithClock(clock))
+		ch := make(chan Fire, 10)
+		if _, err := s.Add(Job{Source: hourly(t, 0), C: ch, Missed: c.policy}); err != nil {
+			t.Fatal(err)
+		}
+		stop := start(s)
+		// Suspended from 8:30 until 11:30.
+		clock.Set(time.Date(1997, 9, 2, 11, 30, 0, 0, time.UTC))
+		clock.Set(time.Date(1997, 9, 2, 12, 0, 0, 0, time.UTC))
+		clock.Set(time.Date(1997, 9, 2, 13, 0, 0, 0, time.UTC))
+		var value []time.Time
+		for len(value) < len(c.want) {
+			value = append(value, receive(t, ch).Time)
+		}
+		stop()
+		if len(ch) != 0 {
+			t.Errorf("policy %d: unexpected extra fires", c.policy)
+		}
+		for i := range value {
+			if !value[i].Equal(c.want[i]) {
+				t.Errorf("policy %d: get %v, want %v", c.policy, value, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSchedulerFunc(t *testing.T) {
+	clock := &fakeClock{now: time.Date(1997, 9, 2, 8, 30, 0, 0, time.UTC)}
+	s := New(WithClock(clock))
+	fired := make(chan Fire, 1)
+	if _, err := s.Add(Job{Source: hourly(t, 1), Func: func(ctx context.Context, f Fire) {
+		fired <- f
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	stop := start(s)
+	defer stop()
+	clock.Set(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC))
+	if f := receive(t, fired); !f.Time.Equal(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("get %v, want 1997-09-02 09:00:00", f.Time)
+	}
+}
+
+func TestSchedulerFuncInOrder(t *testing.T) {
+	clock := &fakeClock{now: time.Date(1997, 9, 2, 8, 30, 0, 0, time.UTC)}
+	s := New(WithClock(clock))
+	started := make(chan Fire, 2)
+	release := make(chan struct{}, 2)
+	if _, err := s.Add(Job{Source: hourly(t, 2), Func: func(ctx context.Context, f Fire) {
+		started <- f
+		<-release
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	stop := start(s)
+	defer stop()
+	defer close(release)
+	clock.Next()
+	if f := receive(t, started); !f.Time.Equal(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("get %v, want 1997-09-02 09:00:00", f.Time)
+	}
+	clock.Next()
+	select {
+	case f := <-started:
+		t.Errorf("get %v while the previous call runs", f.Time)
+	case <-time.After(20 * time.Millisecond):
+	}
+	release <- struct{}{}
+	if f := receive(t, started); !f.Time.Equal(time.Date(1997, 9, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("get %v, want 1997-09-02 10:00:00", f.Time)
+	}
+}
+
+func TestSchedulerJitter(t *testing.T) {
+	clock := &fakeClock{now: time.Date(1997, 9, 2, 8, 30, 0, 0, time.UTC)}
+	s := New(WithClock(clock))
+	c := make(chan Fire, 1)
+	if _, err := s.Add(Job{Source: hourly(t, 1), C: c, Jitter: time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+	stop := start(s)
+	defer stop()
+	now := clock.Next()
+	if now.Before(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)) || !now.Before(time.Date(1997, 9, 2, 9, 1, 0, 0, time.UTC)) {
+		t.Errorf("get timer at %v, want within a minute after 09:00", now)
+	}
+	f := receive(t, c)
+	if !f.Time.Equal(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)) || f.Missed {
+		t.Errorf("get %+v, want an on-time fire at 09:00", f)
+	}
+}
+
+func TestSchedulerAddRemove(t *testing.T) {
+	clock := &fakeClock{now: time.Date(1997, 9, 2, 8, 30, 0, 0, time.UTC)}
+	s := New(WithClock(clock))
+	c := make(chan Fire, 10)
+	removed, _ := s.Add(Job{Source: hourly(t, 0), C: c})
+	stop := start(s)
+	defer stop()
+
+	set := &rrule.Set{}
+	set.RDate(time.Date(1997, 9, 2, 9, 30, 0, 0, time.UTC))
+	added, err := s.Add(Job{Source: set, C: c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Remove(removed)
+	clock.Set(time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC))
+	clock.Set(time.Date(1997, 9, 2, 9, 30, 0, 0, time.UTC))
+	if f := receive(t, c); f.ID != added {
+		t.Errorf("get fire from job %d, want %d", f.ID, added)
+	}
+	if len(c) != 0 {
+		t.Errorf("removed job fired")
+	}
+}
+
+func TestSchedulerAddErrors(t *testing.T) {
+	s := New(WithClock(&fakeClock{now: time.Date(1998, 1, 1, 0, 0, 0, 0, time.UTC)}))
+	if _, err := s.Add(Job{Source: hourly(t, 1)}); err == nil {
+		t.Error("get nil, want error")
+	}
+	if _, err := s.Add(Job{Source: hourly(t, 1), C: make(chan Fire)}); err != ErrNoOccurrence {
+		t.Errorf("get %v, want ErrNoOccurrence", err)
+	}
+}
+
+func TestSchedulerLateWithinTolerance(t *testing.T) {
+	for _, policy := range []MissedPolicy{Skip, CatchUp, RunOnce} {
+		clock := &fakeClock{now: time.Date(1997, 9, 2, 8, 59, 30, 0, time.UTC)}
+		s := New(WithClock(clock), WithTolerance(5*time.Minute))
+		r, err := rrule.NewRRule(rrule.ROption{Freq: rrule.MINUTELY,
+			Dtstart: time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ch := make(chan Fire, 10)
+		if _, err := s.Add(Job{Source: r, C: ch, Missed: policy}); err != nil {
+			t.Fatal(err)
+		}
+		stop := start(s)
+		// Woken up one minute late.
+		clock.Set(time.Date(1997, 9, 2, 9, 1, 0, 0, time.UTC))
+		want := []time.Time{
+			time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC),
+			time.Date(1997, 9, 2, 9, 1, 0, 0, time.UTC),
+		}
+		for _, w := range want {
+			if f := receive(t, ch); !f.Time.Equal(w) || f.Missed {
+				t.Errorf("policy %d: get %+v, want %v", policy, f, w)
+			}
+		}
+		stop()
+		if len(ch) != 0 {
+			t.Errorf("policy %d: unexpected extra fires", policy)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrNotShiftable is returned when no rule can express the occurrences of a
+// rule moved by some duration, e.g. "the last day of the month" moved by a
+// day.
+var ErrNotShiftable = errors.New("cannot be shifted exactly")
+
+func notShiftable(reason string) error {
+	return fmt.Errorf("%w: %s", ErrNotShiftable, reason)
+}
+
+// ShiftROption returns option with every occurrence moved by d on the wall
+// clock of DTSTART, e.g. from Monday 9:00 to Tuesday 10:00 with d = 25h.
+// DTSTART, UNTIL, WKST and the BY* parts are updated together, so that the
+// occurrences of the result are those of option moved by d. Parts that
+// option left to DTSTART are written out when the moved DTSTART no longer
+// implies them. It returns an error wrapping ErrNotShiftable if no rule can
+// express the moved occurrences.
+func ShiftROption(option ROption, d time.Duration) (ROption, error) {
+	if option.Dtstart.IsZero() {
+		return option, notShiftable("DTSTART is not set")
+	}
+	r := buildRRule(option)
+	from := r.dtstart
+	to := shiftWall(from, d)
+	// The date of DTSTART moves by k days and its time of day by c seconds.
+	k := civilDays(to) - civilDays(from)
+	c := secondOfDay(to) - secondOfDay(from)
+
+	result := option
+	result.Dtstart = to
+	if !option.Until.IsZero() {
+		result.Until = shiftWall(option.Until.In(from.Location()), d).In(option.Until.Location())
+	}
+
+	// days is the number of days the date of every occurrence moves by.
+	days := k
+	if r.freq < HOURLY {
+		carry, err := shiftClock(&result, &r, c)
+		if err != nil {
+			return option, err
+		}
+		days += carry
+	} else if c != 0 && (hasDayParts(option) || len(option.Bysetpos) != 0 || len(option.Byhour) != 0 ||
+		len(option.Byminute) != 0 && c%3600 != 0 || len(option.Bysecond) != 0 && c%60 != 0) {
+		return option, notShiftable(fmt.Sprintf("the BY* parts of a %s rule only move by whole days", r.freq))
+	}
+
+	if (r.interval > 1 || len(r.bysetpos) != 0) && days != k {
+		return option, notShiftable("DTSTART would move to another period than the occurrences")
+	}
+	if r.interval > 1 && (r.freq == YEARLY && to.Year() != from.Year() ||
+		r.freq == MONTHLY && (to.Year() != from.Year() || to.Month() != from.Month())) {
+		return option, notShiftable(fmt.Sprintf("DTSTART would move to another %s period", r.freq))
+	}
+	if err := shiftDays(&result, &r, days); err != nil {
+		return option, err
+	}
+	return result, nil
+}
+
+// shiftWall moves t by d on its wall clock, so that a day is 24 hours even
+// across a daylight saving time change.
+func shiftWall(t time.Time, d time.Duration) time.Time {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	return time.Date(year, month, day, hour, minute, second+int(d/time.Second),
+		t.Nanosecond()+int(d%time.Second), t.Location())
+}
+
+// wallDuration returns the duration between the wall clocks of from and to,
+// counting 24 hours per day.
+func wallDuration(from, to time.Time) time.Duration {
+	days := civilDays(to) - civilDays(from)
+	seconds := days*86400 + secondOfDay(to) - secondOfDay(from)
+	return time.Duration(seconds)*time.Second + time.Duration(to.Nanosecond()-from.Nanosecond())
+}
+
+func secondOfDay(t time.Time) int {
+	hour, minute, second := t.Clock()
+	return hour*3600 + minute*60 + second
+}
+
+func hasDayParts(option ROption) bool {
+	return len(option.Bymonth) != 0 || len(option.Byweekno) != 0 || len(option.Byyearday) != 0 ||
+		len(option.Bymonthday) != 0 || len(option.Byweekday) != 0 || len(option.Byeaster) != 0
+}
+
+// shiftClock moves the times of day of a DAILY or coarser rule by c seconds
+// and returns the number of days they all carry over to.
+func shiftClock(result *ROption, r *RRule, c int) (int, error) {
+	days := 0
+	times := map[int]bool{}
+	var hours, minutes, seconds []int
+	for _, hour := range r.byhour {
+		for _, minute := range r.byminute {
+			for _, second := range r.bysecond {
+				day, t := divmod(hour*3600+minute*60+second+c, 86400)
+				if len(times) != 0 && day != days {
+					return 0, notShiftable("the times of day would not all stay on the same day")
+				}
+				days = day
+				times[t] = true
+				hours = appendUnique(hours, t/3600)
+				minutes = appendUnique(minutes, t/60%60)
+				seconds = appendUnique(seconds, t%60)
+			}
+		}
+	}
+	if len(hours)*len(minutes)*len(seconds) != len(times) {
+		return 0, notShiftable("the times of day would not be all the combinations of BYHOUR, BYMINUTE and BYSECOND")
+	}
+	to := result.Dtstart
+	result.Byhour = pinned(r.OrigOptions.Byhour, hours, to.Hour())
+	result.Byminute = pinned(r.OrigOptions.Byminute, minutes, to.Minute())
+	result.Bysecond = pinned(r.OrigOptions.Bysecond, seconds, to.Second())
+	return days, nil
+}
+
+// shiftDays moves the dates selected by the day-level BY* parts of the
+// rule by days.
+func shiftDays(result *ROption, r *RRule, days int) error {
+	eff := r.Options
+	months := append([]int(nil), eff.Bymonth...)
+	monthdays := append([]int(nil), eff.Bymonthday...)
+	yeardays := append([]int(nil), eff.Byyearday...)
+	easter := append([]int(nil), eff.Byeaster...)
+	weekdays := append([]Weekday(nil), eff.Byweekday...)
+
+	if days != 0 {
+		if len(eff.Byweekno) != 0 {
+			return notShiftable("BYWEEKNO")
+		}
+		for i, w := range weekdays {
+			if w.n != 0 && r.freq <= MONTHLY {
+				return notShiftable(fmt.Sprintf("BYDAY=%s", w))
+			}
+			weekdays[i].weekday = pymod(w.weekday+days, 7)
+		}
+		if len(weekdays) != 0 && len(monthdays) == 0 && len(yeardays) == 0 && len(easter) == 0 &&
+			(r.freq == MONTHLY || r.freq == YEARLY) &&
+			(r.interval > 1 || len(r.bysetpos) != 0 || len(months) != 0) {
+			return notShiftable("BYDAY would select days of other months")
+		}
+		if (len(easter) != 0 || len(yeardays) != 0) && len(months) != 0 {
+			return notShiftable("BYMONTH")
+		}
+		if (len(easter) != 0 || len(yeardays) != 0) && r.freq == MONTHLY && (r.interval > 1 || len(r.bysetpos) != 0) {
+			return notShiftable("BYYEARDAY or BYEASTER would select days of other months")
+		}
+		for i := range easter {
+			easter[i] += days
+		}
+		for i, v := range yeardays {
+			if yeardays[i] = v + days; !sameSign(v, v+days, 365) {
+				return notShiftable(fmt.Sprintf("BYYEARDAY=%d", v))
+			}
+		}
+
+		shifted := len(monthdays) != 0
+		for _, v := range monthdays {
+			shifted = shifted && sameSign(v, v+days, 28)
+		}
+		switch {
+		case shifted:
+			for i := range monthdays {
+				monthdays[i] += days
+			}
+		case len(monthdays) != 0 && r.freq == YEARLY && len(months) != 0:
+			var err error
+			if months, monthdays, err = shiftMonthDays(r, months, monthdays, days); err != nil {
+				return err
+			}
+		case len(monthdays) != 0:
+			return notShiftable("BYMONTHDAY would select days of other months")
+		case len(months) != 0:
+			return notShiftable("BYMONTH would select days of other months")
+		}
+
+		if r.freq == WEEKLY && (r.interval > 1 || len(r.bysetpos) != 0) {
+			result.Wkst = Weekday{weekday: pymod(r.wkst+days, 7)}
+		}
+	}
+
+	orig := r.OrigOptions
+	to := result.Dtstart
+	if len(orig.Byweekno) == 0 && len(orig.Byyearday) == 0 && len(orig.Bymonthday) == 0 &&
+		len(orig.Byweekday) == 0 && len(orig.Byeaster) == 0 {
+		// The days were implied by DTSTART.
+		switch r.freq {
+		case YEARLY:
+			// BYMONTH is only implied along with BYMONTHDAY.
+			if result.Bymonthday = pinned(nil, monthdays, to.Day()); result.Bymonthday != nil {
+				result.Bymonth = months
+			} else {
+				result.Bymonth = pinned(orig.Bymonth, months, int(to.Month()))
+			}
+		case MONTHLY:
+			result.Bymonth = months
+			result.Bymonthday = pinned(nil, monthdays, to.Day())
+		case WEEKLY:
+			result.Bymonth = months
+			if len(weekdays) != 1 || weekdays[0].weekday != toPyWeekday(to.Weekday()) {
+				result.Byweekday = weekdays
+			}
+		default:
+			result.Bymonth = months
+		}
+		return nil
+	}
+	result.Bymonth = months
+	result.Bymonthday = monthdays
+	result.Byyearday = yeardays
+	result.Byeaster = easter
+	result.Byweekday = weekdays
+	return nil
+}
+
+// shiftMonthDays moves the dates of a YEARLY rule given by months and
+// positive monthdays to other months, which is exact when every date moves
+// to the same date in leap and common years.
+func shiftMonthDays(r *RRule, months, monthdays []int, days int) ([]int, []int, error) {
+	dates := map[[2]int]bool{}
+	var newMonths, newMonthdays []int
+	for _, m := range months {
+		for _, d := range monthdays {
+			if d < 0 {
+				return nil, nil, notShiftable(fmt.Sprintf("BYMONTHDAY=%d", d))
+			}
+			if d > daysIn(time.Month(m), 2000) {
+				continue // never matches
+			}
+			common := time.Date(2001, time.Month(m), d+days, 0, 0, 0, 0, time.UTC)
+			leap := time.Date(2000, time.Month(m), d+days, 0, 0, 0, 0, time.UTC)
+			if d > daysIn(time.Month(m), 2001) || common.Month() != leap.Month() || common.Day() != leap.Day() {
+				return nil, nil, notShiftable("the dates would move differently in leap years")
+			}
+			if common.Year() != 2001 && (r.interval > 1 || len(r.bysetpos) != 0) {
+				return nil, nil, notShiftable("the dates would move to another year")
+			}
+			dates[[2]int{int(common.Month()), common.Day()}] = true
+			newMonths = appendUnique(newMonths, int(common.Month()))
+			newMonthdays = appendUnique(newMonthdays, common.Day())
+		}
+	}
+	if len(dates) == 0 {
+		return months, monthdays, nil // the rule never matches anyway
+	}
+	for _, m := range newMonths {
+		for _, d := range newMonthdays {
+			if d <= daysIn(time.Month(m), 2000) && !dates[[2]int{m, d}] {
+				return nil, nil, notShiftable("the dates would not be all the combinations of BYMONTH and BYMONTHDAY")
+			}
+		}
+	}
+	return newMonths, newMonthdays, nil
+}
+
+// sameSign reports whether the day numbers v and w are both within 1..max
+// or both within -max..-1, so that they count from the same end of every
+// month or year.
+func sameSign(v, w, max int) bool {
+	return v >= 1 && v <= max && w >= 1 && w <= max || v <= -1 && v >= -max && w <= -1 && w >= -max
+}
+
+// pinned returns values as a BY* part, or nil if the part was left to
+// DTSTART and the moved DTSTART still implies values.
+func pinned(orig []int, values []int, implied int) []int {
+	if len(orig) == 0 && len(values) == 1 && values[0] == implied {
+		return nil
+	}
+	return values
+}
+
+func appendUnique(list []int, v int) []int {
+	if contains(list, v) {
+		return list
+	}
+	list = append(list, v)
+	sort.Ints(list)
+	return list
+}
+
+// Shift returns a copy of the rule with every occurrence moved by d on the
+// wall clock, as ShiftROption does.
+func (r *RRule) Shift(d time.Duration) (*RRule, error) {
+	option := r.OrigOptions
+	option.Dtstart = r.dtstart
+	option, err := ShiftROption(option, d)
+	if err != nil {
+		return nil, err
+	}
+	return r.with(option), nil
+}
+
+// MoveTo returns a copy of the rule moved so that it starts at dtstart, e.g.
+// to move a series from Mondays 9:00 to Tuesdays 10:00.
+func (r *RRule) MoveTo(dtstart time.Time) (*RRule, error) {
+	return r.Shift(wallDuration(r.dtstart, dtstart.In(r.dtstart.Location())))
+}
+
+// Shift returns a copy of the set with its DTSTART, RRULE, RDATEs and
+// EXDATEs moved by d on the wall clock, leaving set unchanged. See
+// ShiftROption.
+func (set *Set) Shift(d time.Duration) (*Set, error) {
+	c := set.clone()
+	if c.rrule != nil {
+		rrule, err := c.rrule.Shift(d)
+		if err != nil {
+			return nil, err
+		}
+		c.rrule = rrule
+	}
+	// RDATEs and EXDATEs move on the wall clock of DTSTART, as the rule does.
+	loc := set.dtstart.Location()
+	if set.rrule != nil {
+		loc = set.rrule.dtstart.Location()
+	}
+	if !c.dtstart.IsZero() {
+		c.dtstart = shiftWall(c.dtstart, d)
+	}
+	for i, rdate := range c.rdate {
+		c.rdate[i] = c.truncate(shiftWall(rdate.In(loc), d).In(rdate.Location()))
+	}
+	for i, exdate := range c.exdate {
+		c.exdate[i] = c.truncate(shiftWall(exdate.In(loc), d).In(exdate.Location()))
+	}
+	return c, nil
+}
+
+// MoveTo returns a copy of the set moved so that it starts at dtstart, as
+// Shift does.
+func (set *Set) MoveTo(dtstart time.Time) (*Set, error) {
+	start := set.dtstart
+	if set.rrule != nil {
+		start = set.rrule.dtstart
+	}
+	return set.Shift(wallDuration(start, dtstart.In(start.Location())))
+}
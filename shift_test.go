@@ -0,0 +1,109 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func testShift(t *testing.T, option ROption, d time.Duration) *RRule {
+	t.Helper()
+	option.Count = 30
+	r, _ := NewRRule(option)
+	shifted, err := r.Shift(d)
+	if err != nil {
+		t.Fatalf("%s: %v", option.String(), err)
+	}
+	want := r.All()
+	for i := range want {
+		want[i] = shiftWall(want[i], d)
+	}
+	if got := shifted.All(); !timesEqual(got, want) {
+		t.Errorf("%s: get %v, want %v", shifted.String(), got, want)
+	}
+	return shifted
+}
+
+func TestShift(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	monday := time.Date(2020, 3, 2, 9, 0, 0, 0, ny)
+	tests := []struct {
+		option ROption
+		d      time.Duration
+		want   string
+	}{
+		{ROption{Freq: WEEKLY}, 25 * time.Hour, "FREQ=WEEKLY;COUNT=30"},
+		{ROption{Freq: WEEKLY, Interval: 2, Byweekday: []Weekday{MO, WE}, Byhour: []int{9}},
+			25 * time.Hour, "FREQ=WEEKLY;INTERVAL=2;WKST=TU;COUNT=30;BYDAY=TU,TH;BYHOUR=10"},
+		{ROption{Freq: DAILY, Byhour: []int{23}}, 2 * time.Hour, "FREQ=DAILY;COUNT=30;BYHOUR=1"},
+		{ROption{Freq: MONTHLY, Bymonthday: []int{10, 20}}, -3 * 24 * time.Hour, "FREQ=MONTHLY;COUNT=30;BYMONTHDAY=7,17"},
+		{ROption{Freq: YEARLY, Dtstart: time.Date(2020, 1, 31, 9, 0, 0, 0, ny)}, 24 * time.Hour, "FREQ=YEARLY;COUNT=30"},
+		{ROption{Freq: YEARLY, Byeaster: []int{0}}, 24 * time.Hour, "FREQ=YEARLY;COUNT=30;BYEASTER=1"},
+		{ROption{Freq: HOURLY, Interval: 5}, 30 * time.Minute, "FREQ=HOURLY;INTERVAL=5;COUNT=30"},
+	}
+	for _, test := range tests {
+		if test.option.Dtstart.IsZero() {
+			test.option.Dtstart = monday
+		}
+		shifted := testShift(t, test.option, test.d)
+		if got := shifted.OrigOptions.RRuleString(); got != test.want {
+			t.Errorf("get %v, want %v", got, test.want)
+		}
+	}
+}
+
+func TestShiftNotShiftable(t *testing.T) {
+	dtstart := time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC)
+	tests := []ROption{
+		{Freq: MONTHLY, Bymonthday: []int{-1}},
+		{Freq: MONTHLY, Byweekday: []Weekday{MO.Nth(1)}},
+		{Freq: DAILY, Bymonth: []int{3}},
+		{Freq: DAILY, Byhour: []int{9, 20}},
+	}
+	for _, option := range tests {
+		option.Dtstart = dtstart
+		if _, err := ShiftROption(option, 5*time.Hour+24*time.Hour); !errors.Is(err, ErrNotShiftable) {
+			t.Errorf("%s: get %v, want %v", option.String(), err, ErrNotShiftable)
+		}
+	}
+}
+
+func TestSetMoveTo(t *testing.T) {
+	set, err := StrToRRuleSet(`DTSTART;TZID=America/New_York:20200302T090000
+RRULE:FREQ=WEEKLY;COUNT=4
+EXDATE;TZID=America/New_York:20200309T090000
+RDATE;TZID=America/New_York:20200311T090000`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved, err := set.MoveTo(set.GetDTStart().AddDate(0, 0, 1).Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := set.All()
+	for i := range want {
+		want[i] = want[i].AddDate(0, 0, 1).Add(time.Hour)
+	}
+	if got := moved.All(); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+}
+
+func TestSetShiftExDateLocation(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	set := &Set{}
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 4, Dtstart: time.Date(2020, 3, 6, 9, 0, 0, 0, ny)})
+	set.RRule(r)
+	// 9:00 in New York on March 7, before the change to daylight saving time.
+	set.ExDate(time.Date(2020, 3, 7, 14, 0, 0, 0, time.UTC))
+	shifted, err := set.Shift(24 * time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{time.Date(2020, 3, 7, 9, 0, 0, 0, ny),
+		time.Date(2020, 3, 9, 9, 0, 0, 0, ny),
+		time.Date(2020, 3, 10, 9, 0, 0, 0, ny)}
+	if got := shifted.All(); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotOccurrence is returned when splitting a set at a time that is not one
+// of its occurrences.
+var ErrNotOccurrence = errors.New("not an occurrence")
+
+// Split cuts the set at its occurrence at, as when a series is edited "from
+// this occurrence onward". It returns the occurrences before at as head and
+// the others as tail, leaving set unchanged:
+//   - the RRULE of head ends with an UNTIL just before at, in UTC as RFC 5545
+//     requires, or keeps its UNTIL if it is earlier;
+//   - the tail starts at at, and its RRULE at the first occurrence of the
+//     rule from at on, with COUNT reduced by the occurrences left in head;
+//   - RDATEs and EXDATEs go to the half they fall in.
+//
+// The tail has no RRULE if the rule has no occurrence from at on.
+func (set *Set) Split(at time.Time) (head, tail *Set, err error) {
+	if !set.Contains(at) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotOccurrence, timeToStr(at))
+	}
+
+	head, tail = set.clone(), set.clone()
+	head.rdate, tail.rdate = splitTimes(set.rdate, set.truncate(at))
+	head.exdate, tail.exdate = splitTimes(set.exdate, set.truncate(at))
+	tail.dtstart = set.truncate(at)
+
+	if r := set.rrule; r != nil {
+		start := r.After(at, true)
+		if start.IsZero() {
+			// The rule ends before at.
+			tail.rrule = nil
+		} else {
+			until := at.Add(-set.GetPrecision()).UTC()
+			option := r.OrigOptions
+			if option.Until.IsZero() || until.Before(option.Until) {
+				option.Count, option.Until = 0, until
+				head.rrule = r.with(option)
+			}
+
+			option = r.OrigOptions
+			if option.Count != 0 {
+				option.Count -= r.CountBetween(time.Time{}, at, false)
+			}
+			option.Dtstart = start
+			tail.rrule = r.with(option)
+		}
+	}
+	return head, tail, nil
+}
+
+// splitTimes returns the times of s before at, and the others.
+func splitTimes(s []time.Time, at time.Time) (before, after []time.Time) {
+	for _, t := range s {
+		if t.Before(at) {
+			before = append(before, t)
+		} else {
+			after = append(after, t)
+		}
+	}
+	return before, after
+}
@@ -0,0 +1,98 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetSplit(t *testing.T) {
+	set, err := StrToRRuleSet(`DTSTART;TZID=America/New_York:20200106T090000
+RRULE:FREQ=WEEKLY;COUNT=6
+RDATE;TZID=America/New_York:20200108T090000,20200130T090000
+EXDATE;TZID=America/New_York:20200113T090000,20200203T090000`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := set.All()
+	at := all[3]
+	head, tail, err := set.Split(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := append(head.All(), tail.All()...); !timesEqual(got, all) {
+		t.Errorf("get %v, want %v", got, all)
+	}
+	if got := tail.All(); !got[0].Equal(at) {
+		t.Errorf("get %v, want %v", got[0], at)
+	}
+	if got := head.GetRRule().String(); got != "DTSTART;TZID=America/New_York:20200106T090000\nFREQ=WEEKLY;UNTIL=20200127T135959Z" {
+		t.Errorf("get %v", got)
+	}
+	// Jan 6, 13, 20 are before the split, so 3 of the 6 occurrences remain.
+	if got := tail.GetRRule().String(); got != "DTSTART;TZID=America/New_York:20200127T090000\nFREQ=WEEKLY;COUNT=3" {
+		t.Errorf("get %v", got)
+	}
+	if len(head.GetExDate()) != 1 || len(tail.GetExDate()) != 1 || len(tail.GetRDate()) != 1 {
+		t.Errorf("get %v %v %v, want EXDATEs and RDATEs partitioned", head.GetExDate(), tail.GetExDate(), tail.GetRDate())
+	}
+	if len(set.All()) != len(all) {
+		t.Errorf("split changed the set")
+	}
+
+	if _, _, err := set.Split(at.Add(time.Hour)); !errors.Is(err, ErrNotOccurrence) {
+		t.Errorf("get %v, want %v", err, ErrNotOccurrence)
+	}
+}
+
+func TestSetSplitAtRDate(t *testing.T) {
+	set, err := StrToRRuleSet("DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY;COUNT=3\nRDATE:20200105T090000Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, tail, err := set.Split(time.Date(2020, 1, 5, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tail.GetRRule() != nil || len(tail.All()) != 1 || len(head.All()) != 3 {
+		t.Errorf("get %v and %v", head.All(), tail.All())
+	}
+}
+
+func TestSetSplitBeforeRule(t *testing.T) {
+	set, err := StrToRRuleSet("DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY;COUNT=3\nRDATE:20200102T120000Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+	head, tail, err := set.Split(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tail.GetDTStart(); !got.Equal(at) {
+		t.Errorf("get %v, want %v", got, at)
+	}
+	want := []time.Time{at, time.Date(2020, 1, 3, 9, 0, 0, 0, time.UTC)}
+	if got := tail.All(); !timesEqual(got, want) {
+		t.Errorf("get %v, want %v", got, want)
+	}
+	if got := head.All(); len(got) != 2 {
+		t.Errorf("get %v, want 2 occurrences", got)
+	}
+}
+
+func TestSetSplitSubsecond(t *testing.T) {
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 3, Subsecond: true,
+		Dtstart: time.Date(2020, 1, 1, 9, 0, 0, 500000000, time.UTC)})
+	set := &Set{}
+	set.RRule(r)
+	all := set.All()
+	head, tail, err := set.Split(all[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := append(head.All(), tail.All()...); !timesEqual(got, all) {
+		t.Errorf("get %v, want %v", got, all)
+	}
+}
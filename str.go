@@ -22,6 +22,25 @@ func timeToStr(time time.Time) string {
 }
 
 func strToTimeInLoc(str string, loc *time.Location) (time.Time, error) {
+	// Years before 0 or after 9999 have a sign or more digits, as time
+	// formats them, which RFC 5545 does not allow.
+	if date := strings.SplitN(str, "T", 2)[0]; len(date) > len(DateFormat) {
+		split := len(date) - len("0102")
+		year, err := strconv.Atoi(str[:split])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid year in %s", str)
+		}
+		// 2000 is a leap year, so that February 29 parses.
+		t, err := strToTimeInLoc("2000"+str[split:], loc)
+		if err != nil {
+			return t, err
+		}
+		if t.Month() == time.February && t.Day() == 29 && isLeap(year) == 0 {
+			return time.Time{}, fmt.Errorf("day out of range in %s", str)
+		}
+		hour, minute, second := t.Clock()
+		return time.Date(year, t.Month(), t.Day(), hour, minute, second, t.Nanosecond(), t.Location()), nil
+	}
 	if len(str) == len(DateFormat) {
 		return time.ParseInLocation(DateFormat, str, loc)
 	}
@@ -50,6 +69,21 @@ func StrToFreq(str string) (Frequency, error) {
 	return result, nil
 }
 
+func (c Computus) String() string {
+	return [...]string{"WESTERN", "ORTHODOX"}[c]
+}
+
+// StrToComputus converts the value of a COMPUTUS rule part to a Computus.
+func StrToComputus(str string) (Computus, error) {
+	switch str {
+	case "WESTERN":
+		return Western, nil
+	case "ORTHODOX":
+		return Orthodox, nil
+	}
+	return 0, errors.New("undefined computus: " + str)
+}
+
 func (wday Weekday) String() string {
 	s := [...]string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}[wday.weekday]
 	if wday.n == 0 {
@@ -83,11 +117,13 @@ func strToWeekdays(value string) ([]Weekday, error) {
 	contents := strings.Split(value, ",")
 	result := make([]Weekday, len(contents))
 	var e error
+	offset := 0
 	for i, s := range contents {
 		result[i], e = strToWeekday(s)
 		if e != nil {
-			return nil, e
+			return nil, &ParseError{Offset: offset, Value: s, Kind: ErrInvalidValue, Err: e}
 		}
+		offset += len(s) + 1
 	}
 	return result, nil
 }
@@ -107,11 +143,13 @@ func strToInts(value string) ([]int, error) {
 	contents := strings.Split(value, ",")
 	result := make([]int, len(contents))
 	var e error
+	offset := 0
 	for i, s := range contents {
 		result[i], e = strconv.Atoi(s)
 		if e != nil {
-			return nil, e
+			return nil, &ParseError{Offset: offset, Value: s, Kind: ErrInvalidValue, Err: e}
 		}
+		offset += len(s) + 1
 	}
 	return result, nil
 }
@@ -159,6 +197,10 @@ func (option *ROption) RRuleString() string {
 	result = appendIntsOption(result, "BYMINUTE", option.Byminute)
 	result = appendIntsOption(result, "BYSECOND", option.Bysecond)
 	result = appendIntsOption(result, "BYEASTER", option.Byeaster)
+	if option.Computus != Western {
+		result = append(result, fmt.Sprintf("COMPUTUS=%v", option.Computus))
+	}
+	result = append(result, option.Extensions...)
 	return strings.Join(result, ";")
 }
 
@@ -171,17 +213,146 @@ func StrToROption(rfcString string) (*ROption, error) {
 // time is supplied as date-time/date field (ex. UNTIL), it is parsed
 // as a time in a given location (time zone)
 func StrToROptionInLocation(rfcString string, loc *time.Location) (*ROption, error) {
+	p := parser{}
+	return p.parseROption(rfcString, loc)
+}
+
+// ParseMode selects how strictly RRULE strings are parsed.
+type ParseMode int
+
+// Parse modes
+const (
+	// Strict rejects anything this package does not understand.
+	Strict ParseMode = iota
+	// Lenient fixes up or skips recoverable problems found in real-world
+	// feeds and reports each of them as a warning: empty rule parts and
+	// list items, lower-case names and values, unknown or invalid rule
+	// parts, and dates in ISO 8601 or with a numeric UTC offset. X- rule
+	// parts are kept in ROption.Extensions as written.
+	Lenient
+)
+
+// ParseOptions configures StrToROptionWithOptions, StrToRRuleWithOptions and
+// StrToRRuleSetWithOptions.
+type ParseOptions struct {
+	Mode ParseMode
+	// Location is used for local times without TZID. It defaults to UTC.
+	Location *time.Location
+	// RFC5545 rejects rules that violate RFC 5545, as ValidateRFC5545
+	// does, and also checks that UNTIL has the value type of DTSTART.
+	RFC5545 bool
+}
+
+func (opts ParseOptions) location() *time.Location {
+	if opts.Location == nil {
+		return time.UTC
+	}
+	return opts.Location
+}
+
+// StrToROptionWithOptions is same as StrToROptionInLocation, with the parse
+// mode given in opts. It also returns the problems fixed up or skipped in
+// Lenient mode.
+func StrToROptionWithOptions(rfcString string, opts ParseOptions) (*ROption, []*ParseError, error) {
+	p := newParser(opts)
+	option, err := p.parseROption(rfcString, opts.location())
+	return option, p.warnings, err
+}
+
+// parser holds the state of a Strict or Lenient parse.
+type parser struct {
+	lenient  bool
+	rfc5545  bool
+	warnings []*ParseError
+	// dtstart is the value type of DTSTART, once parsed.
+	dtstart valueForm
+	// bounds rejects values out of range, as NewRRule does, but located
+	// in the string.
+	bounds bool
+}
+
+func newParser(opts ParseOptions) parser {
+	return parser{lenient: opts.Mode == Lenient, rfc5545: opts.RFC5545}
+}
+
+// fail returns err in strict mode. In lenient mode it records err as a
+// warning and returns nil, so that the offending token is skipped.
+func (p *parser) fail(err *ParseError) error {
+	if !p.lenient {
+		return err
+	}
+	p.warnings = append(p.warnings, err)
+	return nil
+}
+
+// list drops empty and invalid items from a comma separated value in
+// lenient mode. at locates value.
+func (p *parser) list(value string, valid func(string) error, at ParseError) string {
+	if !p.lenient {
+		return value
+	}
+	var items []string
+	offset := at.Offset
+	for _, item := range strings.Split(value, ",") {
+		at.Offset, at.Value = offset, item
+		offset += len(item) + 1
+		if item == "" {
+			w := at
+			w.Kind, w.Err = ErrSyntax, errors.New("empty list item")
+			p.fail(&w)
+			continue
+		}
+		if err := valid(item); err != nil {
+			w := at
+			w.Kind, w.Err = ErrInvalidValue, err
+			p.fail(&w)
+			continue
+		}
+		items = append(items, item)
+	}
+	return strings.Join(items, ",")
+}
+
+// lenientTimeLayouts are tried in lenient mode for dates that are not in
+// the iCalendar format.
+var lenientTimeLayouts = []string{
+	"20060102T150405Z0700",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// time parses a DATE or DATE-TIME value, trying lenientTimeLayouts in
+// lenient mode.
+func (p *parser) time(value string, loc *time.Location, at ParseError) (time.Time, error) {
+	t, err := strToTimeInLoc(value, loc)
+	if err == nil || !p.lenient {
+		return t, err
+	}
+	for _, layout := range lenientTimeLayouts {
+		if t, e := time.ParseInLocation(layout, value, loc); e == nil {
+			at.Value, at.Kind, at.Err = value, ErrInvalidValue, fmt.Errorf("non-iCalendar date %s", value)
+			p.fail(&at)
+			return t, nil
+		}
+	}
+	return t, err
+}
+
+func (p *parser) parseROption(rfcString string, loc *time.Location) (*ROption, error) {
 	rfcString = strings.TrimSpace(rfcString)
 	strs := strings.Split(rfcString, "\n")
 	var rruleStr, dtstartStr string
+	line := 1
 	switch len(strs) {
 	case 1:
 		rruleStr = strs[0]
 	case 2:
 		dtstartStr = strs[0]
 		rruleStr = strs[1]
+		line = 2
 	default:
-		return nil, errors.New("invalid RRULE string")
+		return nil, &ParseError{Kind: ErrSyntax, Err: errors.New("invalid RRULE string")}
 	}
 
 	result := ROption{}
@@ -190,34 +361,110 @@ func StrToROptionInLocation(rfcString string, loc *time.Location) (*ROption, err
 	if dtstartStr != "" {
 		firstName, err := processRRuleName(dtstartStr)
 		if err != nil {
-			return nil, fmt.Errorf("expect DTSTART but: %s", err)
+			return nil, locateError(err, ErrSyntax, 1, "", "", 0, "")
 		}
 		if firstName != "DTSTART" {
-			return nil, fmt.Errorf("expect DTSTART but: %s", firstName)
+			return nil, &ParseError{Line: 1, Property: firstName, Value: firstName,
+				Kind: ErrMissingProperty, Err: fmt.Errorf("expect DTSTART but: %s", firstName)}
 		}
 
+		p.dtstart = formOf(dtstartStr[len(firstName)+1:])
 		result.Dtstart, err = StrToDtStart(dtstartStr[len(firstName)+1:], loc)
+		if err != nil && p.lenient {
+			value := strings.TrimSpace(dtstartStr[len(firstName)+1:])
+			result.Dtstart, err = p.time(value, loc, ParseError{Line: 1, Property: firstName, Offset: len(firstName) + 1})
+		}
 		if err != nil {
-			return nil, fmt.Errorf("StrToDtStart failed: %s", err)
+			return nil, locateError(err, ErrInvalidValue, 1, "DTSTART", "", len(firstName)+1, "")
 		}
 	}
 
+	if p.lenient && strings.HasPrefix(strings.ToUpper(rruleStr), "RRULE:") {
+		p.fail(&ParseError{Line: line, Property: "RRULE", Value: rruleStr[:len("RRULE:")], Kind: ErrSyntax,
+			Err: errors.New("unexpected RRULE: prefix")})
+		rruleStr = rruleStr[len("RRULE:"):]
+	}
+
+	offset := 0
+	offsets := map[string]int{}
+	values := map[string]string{}
+	until := formUnknown
 	for _, attr := range strings.Split(rruleStr, ";") {
+		attrOffset := offset
+		offset += len(attr) + 1
+		if p.lenient {
+			attr = strings.TrimSpace(attr)
+			if attr == "" {
+				p.fail(&ParseError{Line: line, Property: "RRULE", Offset: attrOffset, Kind: ErrSyntax,
+					Err: errors.New("empty rule part")})
+				continue
+			}
+		}
 		keyValue := strings.Split(attr, "=")
 		if len(keyValue) != 2 {
-			return nil, errors.New("wrong format")
+			if err := p.fail(&ParseError{Line: line, Property: "RRULE", Offset: attrOffset, Value: attr, Kind: ErrSyntax}); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		key, value := keyValue[0], keyValue[1]
+		if p.lenient {
+			upper := strings.ToUpper(key)
+			if strings.HasPrefix(upper, "X-") {
+				// Extensions keep their case to round-trip.
+				result.Extensions = append(result.Extensions, key+"="+value)
+				continue
+			}
+			if upper != key {
+				p.fail(&ParseError{Line: line, Property: "RRULE", Offset: attrOffset, Value: key, Kind: ErrSyntax,
+					Err: fmt.Errorf("lower-case rule part name: %s", key)})
+				key = upper
+			}
+			if upper := strings.ToUpper(value); upper != value {
+				p.fail(&ParseError{Line: line, Property: "RRULE", Param: key, Offset: attrOffset + len(key) + 1,
+					Value: value, Kind: ErrInvalidValue, Err: fmt.Errorf("lower-case value: %s", value)})
+				value = upper
+			}
+		}
 		if len(value) == 0 {
-			return nil, errors.New(key + " option has no value")
+			if err := p.fail(&ParseError{Line: line, Property: "RRULE", Param: key, Offset: attrOffset, Kind: ErrMissingValue}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		at := ParseError{Line: line, Property: "RRULE", Param: key, Offset: attrOffset + len(key) + 1}
+		offsets[key] = at.Offset
+		values[key] = value
+		validInt := func(s string) error {
+			_, err := strconv.Atoi(s)
+			return err
+		}
+		ints := func() ([]int, error) {
+			value := p.list(value, validInt, at)
+			if value == "" {
+				// Every item was dropped with a warning.
+				return nil, nil
+			}
+			return strToInts(value)
+		}
+		weekdays := func() ([]Weekday, error) {
+			value := p.list(value, func(s string) error {
+				_, err := strToWeekday(s)
+				return err
+			}, at)
+			if value == "" {
+				return nil, nil
+			}
+			return strToWeekdays(value)
 		}
 		var e error
 		switch key {
 		case "FREQ":
 			result.Freq, e = StrToFreq(value)
-			freqSet = true
+			freqSet = e == nil
 		case "DTSTART":
-			result.Dtstart, e = strToTimeInLoc(value, loc)
+			p.dtstart = formOf(value)
+			result.Dtstart, e = p.time(value, loc, at)
 		case "INTERVAL":
 			result.Interval, e = strconv.Atoi(value)
 		case "WKST":
@@ -225,32 +472,43 @@ func StrToROptionInLocation(rfcString string, loc *time.Location) (*ROption, err
 		case "COUNT":
 			result.Count, e = strconv.Atoi(value)
 		case "UNTIL":
-			result.Until, e = strToTimeInLoc(value, loc)
+			until = formOf(value)
+			result.Until, e = p.time(value, loc, at)
 		case "BYSETPOS":
-			result.Bysetpos, e = strToInts(value)
+			result.Bysetpos, e = ints()
 		case "BYMONTH":
-			result.Bymonth, e = strToInts(value)
+			result.Bymonth, e = ints()
 		case "BYMONTHDAY":
-			result.Bymonthday, e = strToInts(value)
+			result.Bymonthday, e = ints()
 		case "BYYEARDAY":
-			result.Byyearday, e = strToInts(value)
+			result.Byyearday, e = ints()
 		case "BYWEEKNO":
-			result.Byweekno, e = strToInts(value)
+			result.Byweekno, e = ints()
 		case "BYDAY":
-			result.Byweekday, e = strToWeekdays(value)
+			result.Byweekday, e = weekdays()
 		case "BYHOUR":
-			result.Byhour, e = strToInts(value)
+			result.Byhour, e = ints()
 		case "BYMINUTE":
-			result.Byminute, e = strToInts(value)
+			result.Byminute, e = ints()
 		case "BYSECOND":
-			result.Bysecond, e = strToInts(value)
+			result.Bysecond, e = ints()
 		case "BYEASTER":
-			result.Byeaster, e = strToInts(value)
+			result.Byeaster, e = ints()
+		case "COMPUTUS":
+			result.Computus, e = StrToComputus(value)
 		default:
-			return nil, errors.New("unknown RRULE property: " + key)
+			if err := p.fail(&ParseError{Line: line, Property: "RRULE", Offset: attrOffset, Value: key, Kind: ErrUnknownProperty}); err != nil {
+				return nil, err
+			}
 		}
 		if e != nil {
-			return nil, e
+			err := locateError(e, ErrInvalidValue, line, "RRULE", key, at.Offset, value)
+			if !p.lenient {
+				return nil, err
+			}
+			var pe *ParseError
+			errors.As(err, &pe)
+			p.fail(pe)
 		}
 	}
 	if !freqSet {
@@ -258,11 +516,60 @@ func StrToROptionInLocation(rfcString string, loc *time.Location) (*ROption, err
 		// parameter. We'll just confirm it exists because we do not
 		// have a meaningful default nor a way to confirm if we parsed
 		// a value from the options this returns.
-		return nil, errors.New("RRULE property FREQ is required")
+		return nil, &ParseError{Line: line, Property: "RRULE", Param: "FREQ", Kind: ErrMissingProperty,
+			Err: errors.New("RRULE property FREQ is required")}
+	}
+	// Locate values out of range at the list item, as NewRRule cannot.
+	locate := func(err *ParseError) {
+		offset := offsets[err.Param]
+		if err.Value != "" {
+			offset += itemOffset(values[err.Param], err.Value)
+		}
+		locateError(err, nil, line, "RRULE", "", offset, "")
+	}
+	if p.bounds && !p.rfc5545 {
+		if errs := boundsErrors(result); len(errs) != 0 {
+			locate(errs[0])
+			return nil, errs[0]
+		}
+	} else if p.rfc5545 {
+		errs := append(boundsErrors(result), rfcViolations(result)...)
+		err := untilFormViolation(p.dtstart, until)
+		if p.dtstart == formUnknown || until == formUnknown {
+			err = untilViolation(result)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, err := range errs {
+			locate(err)
+		}
+		if err := validationError(errs); err != nil {
+			return nil, err
+		}
 	}
 	return &result, nil
 }
 
+// itemOffset returns the offset in the list value of its item equal to
+// item, as boundsErrors writes values, or 0 if there is none.
+func itemOffset(value, item string) int {
+	offset := 0
+	for _, s := range strings.Split(value, ",") {
+		normal := s
+		if n, err := strconv.Atoi(s); err == nil {
+			normal = strconv.Itoa(n)
+		} else if w, err := strToWeekday(s); err == nil {
+			normal = w.String()
+		}
+		if normal == item {
+			return offset
+		}
+		offset += len(s) + 1
+	}
+	return 0
+}
+
 func (r *RRule) String() string {
 	return r.OrigOptions.String()
 }
@@ -274,18 +581,33 @@ func (set *Set) String() string {
 
 // StrToRRule converts string to RRule
 func StrToRRule(rfcString string) (*RRule, error) {
-	option, e := StrToROption(rfcString)
+	p := parser{bounds: true}
+	option, e := p.parseROption(rfcString, time.UTC)
 	if e != nil {
 		return nil, e
 	}
 	return NewRRule(*option)
 }
 
+// StrToRRuleWithOptions is same as StrToRRule, with the parse mode, default
+// location and RFC 5545 validation given in opts. It also returns the
+// problems fixed up or skipped in Lenient mode.
+func StrToRRuleWithOptions(rfcString string, opts ParseOptions) (*RRule, []*ParseError, error) {
+	p := newParser(opts)
+	p.bounds = true
+	option, err := p.parseROption(rfcString, opts.location())
+	if err != nil {
+		return nil, p.warnings, err
+	}
+	r, err := NewRRule(*option)
+	return r, p.warnings, err
+}
+
 // StrToRRuleSet converts string to RRuleSet
 func StrToRRuleSet(s string) (*Set, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
-		return nil, errors.New("empty string")
+		return nil, &ParseError{Kind: ErrSyntax, Err: errors.New("empty string")}
 	}
 	ss := strings.Split(s, "\n")
 	return StrSliceToRRuleSet(ss)
@@ -301,53 +623,102 @@ func StrSliceToRRuleSet(ss []string) (*Set, error) {
 // StrSliceToRRuleSetInLoc is same as StrSliceToRRuleSet, but by default parses local times
 // in specified default location
 func StrSliceToRRuleSetInLoc(ss []string, defaultLoc *time.Location) (*Set, error) {
-	if len(ss) == 0 {
-		return &Set{}, nil
+	p := parser{}
+	return p.parseSet(ss, defaultLoc)
+}
+
+// StrToRRuleSetWithOptions is same as StrToRRuleSet, with the parse mode,
+// default location and RFC 5545 validation given in opts. It also returns the problems fixed up or
+// skipped in Lenient mode, where blank lines and unknown properties are
+// skipped as well.
+func StrToRRuleSetWithOptions(s string, opts ParseOptions) (*Set, []*ParseError, error) {
+	p := newParser(opts)
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil, &ParseError{Kind: ErrSyntax, Err: errors.New("empty string")}
 	}
+	set, err := p.parseSet(strings.Split(s, "\n"), opts.location())
+	return set, p.warnings, err
+}
 
+func (p *parser) parseSet(ss []string, defaultLoc *time.Location) (*Set, error) {
 	set := Set{}
+	p.bounds = true
+
+	lines := make([]int, 0, len(ss))
+	for i, line := range ss {
+		if p.lenient {
+			ss[i] = strings.TrimSpace(line)
+			if ss[i] == "" {
+				p.fail(&ParseError{Line: i + 1, Kind: ErrSyntax, Err: errors.New("bad format: empty line")})
+				continue
+			}
+		}
+		lines = append(lines, i)
+	}
+	if len(lines) == 0 {
+		return &set, nil
+	}
 
 	// According to RFC DTSTART is always the first line.
-	firstName, err := processRRuleName(ss[0])
+	first := ss[lines[0]]
+	firstName, err := processRRuleName(first)
 	if err != nil {
-		return nil, err
+		return nil, locateError(err, ErrSyntax, lines[0]+1, "", "", 0, "")
 	}
 	if firstName == "DTSTART" {
-		dt, err := StrToDtStart(ss[0][len(firstName)+1:], defaultLoc)
+		p.dtstart = formOf(first[len(firstName)+1:])
+		dt, err := StrToDtStart(first[len(firstName)+1:], defaultLoc)
+		if err != nil && p.lenient {
+			dt, err = p.time(first[len(firstName)+1:], defaultLoc,
+				ParseError{Line: lines[0] + 1, Property: firstName, Offset: len(firstName) + 1})
+		}
 		if err != nil {
-			return nil, fmt.Errorf("StrToDtStart failed: %v", err)
+			return nil, locateError(err, ErrInvalidValue, lines[0]+1, firstName, "", len(firstName)+1, "")
 		}
 		// default location should be taken from DTSTART property to correctly
 		// parse local times met in RDATE,EXDATE and other rules
 		defaultLoc = dt.Location()
 		set.DTStart(dt)
 		// We've processed the first one
-		ss = ss[1:]
+		lines = lines[1:]
 	}
 
-	for _, line := range ss {
+	for _, i := range lines {
+		line := ss[i]
 		name, err := processRRuleName(line)
 		if err != nil {
-			return nil, err
+			if err := p.fail(locateError(err, ErrSyntax, i+1, "", "", 0, "").(*ParseError)); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		rule := line[len(name)+1:]
 
 		switch name {
 		case "RRULE":
-			rOpt, err := StrToROptionInLocation(rule, defaultLoc)
+			warnings := len(p.warnings)
+			rOpt, err := p.parseROption(rule, defaultLoc)
+			for _, w := range p.warnings[warnings:] {
+				locateError(w, nil, i+1, name, "", len(name)+1, "")
+			}
 			if err != nil {
-				return nil, fmt.Errorf("StrToROption failed: %v", err)
+				return nil, locateError(err, ErrInvalidValue, i+1, name, "", len(name)+1, "")
 			}
 			r, err := NewRRule(*rOpt)
 			if err != nil {
-				return nil, fmt.Errorf("NewRRule failed: %v", r)
+				return nil, locateError(err, ErrOutOfRange, i+1, name, "", len(name)+1, "")
 			}
 
 			set.RRule(r)
 		case "RDATE", "EXDATE":
 			ts, err := StrToDatesInLoc(rule, defaultLoc)
 			if err != nil {
-				return nil, fmt.Errorf("strToDates failed: %v", err)
+				err = locateError(err, ErrInvalidValue, i+1, name, "", len(name)+1, "")
+				if err := p.fail(err.(*ParseError)); err != nil {
+					return nil, err
+				}
+				continue
 			}
 			for _, t := range ts {
 				if name == "RDATE" {
@@ -356,6 +727,10 @@ func StrSliceToRRuleSetInLoc(ss []string, defaultLoc *time.Location) (*Set, erro
 					set.ExDate(t)
 				}
 			}
+		default:
+			if p.lenient {
+				p.fail(&ParseError{Line: i + 1, Property: name, Value: name, Kind: ErrUnknownProperty})
+			}
 		}
 	}
 
@@ -387,29 +762,33 @@ func StrToDates(str string) (ts []time.Time, err error) {
 func StrToDatesInLoc(str string, defaultLoc *time.Location) (ts []time.Time, err error) {
 	tmp := strings.Split(str, ":")
 	if len(tmp) > 2 {
-		return nil, fmt.Errorf("bad format")
+		return nil, &ParseError{Value: str, Kind: ErrSyntax, Err: errors.New("bad format")}
 	}
 	loc := defaultLoc
+	offset := 0
 	if len(tmp) == 2 {
 		params := strings.Split(tmp[0], ";")
 		for _, param := range params {
 			if strings.HasPrefix(param, "TZID=") {
 				loc, err = parseTZID(param)
+				if err != nil {
+					return nil, &ParseError{Param: "TZID", Offset: offset, Value: param, Kind: ErrInvalidValue, Err: err}
+				}
 			} else if param != "VALUE=DATE-TIME" && param != "VALUE=DATE" {
-				err = fmt.Errorf("unsupported: %v", param)
-			}
-			if err != nil {
-				return nil, fmt.Errorf("bad dates param: %s", err.Error())
+				return nil, &ParseError{Param: strings.SplitN(param, "=", 2)[0], Offset: offset, Value: param,
+					Kind: ErrInvalidValue, Err: fmt.Errorf("unsupported: %v", param)}
 			}
+			offset += len(param) + 1
 		}
 		tmp = tmp[1:]
 	}
 	for _, datestr := range strings.Split(tmp[0], ",") {
 		t, err := strToTimeInLoc(datestr, loc)
 		if err != nil {
-			return nil, fmt.Errorf("strToTime failed: %v", err)
+			return nil, &ParseError{Offset: offset, Value: datestr, Kind: ErrInvalidValue, Err: err}
 		}
 		ts = append(ts, t)
+		offset += len(datestr) + 1
 	}
 	return
 }
@@ -418,17 +797,17 @@ func StrToDatesInLoc(str string, defaultLoc *time.Location) (ts []time.Time, err
 func processRRuleName(line string) (string, error) {
 	line = strings.ToUpper(strings.TrimSpace(line))
 	if line == "" {
-		return "", fmt.Errorf("bad format %v", line)
+		return "", &ParseError{Kind: ErrSyntax, Err: errors.New("bad format: empty line")}
 	}
 
 	nameLen := strings.IndexAny(line, ";:")
 	if nameLen <= 0 {
-		return "", fmt.Errorf("bad format %v", line)
+		return "", &ParseError{Value: line, Kind: ErrSyntax}
 	}
 
 	name := line[:nameLen]
 	if strings.IndexAny(name, "=") > 0 {
-		return "", fmt.Errorf("bad format %v", line)
+		return "", &ParseError{Value: line, Kind: ErrSyntax}
 	}
 
 	return name, nil
@@ -439,19 +818,25 @@ func processRRuleName(line string) (string, error) {
 func StrToDtStart(str string, defaultLoc *time.Location) (time.Time, error) {
 	tmp := strings.Split(str, ":")
 	if len(tmp) > 2 || len(tmp) == 0 {
-		return time.Time{}, fmt.Errorf("bad format")
+		return time.Time{}, &ParseError{Value: str, Kind: ErrSyntax, Err: errors.New("bad format")}
 	}
 
+	offset := 0
+	loc := defaultLoc
 	if len(tmp) == 2 {
 		// tzid
-		loc, err := parseTZID(tmp[0])
+		var err error
+		loc, err = parseTZID(tmp[0])
 		if err != nil {
-			return time.Time{}, err
+			return time.Time{}, &ParseError{Param: "TZID", Value: tmp[0], Kind: ErrInvalidValue, Err: err}
 		}
-		return strToTimeInLoc(tmp[1], loc)
+		offset = len(tmp[0]) + 1
+	}
+	t, err := strToTimeInLoc(tmp[len(tmp)-1], loc)
+	if err != nil {
+		return time.Time{}, &ParseError{Offset: offset, Value: tmp[len(tmp)-1], Kind: ErrInvalidValue, Err: err}
 	}
-	// no tzid, len == 1
-	return strToTimeInLoc(tmp[0], defaultLoc)
+	return t, nil
 }
 
 func parseTZID(s string) (*time.Location, error) {
@@ -1,6 +1,7 @@
 package rrule
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -50,6 +51,39 @@ func TestCompatibility(t *testing.T) {
 	}
 }
 
+func TestComputusStr(t *testing.T) {
+	str := "DTSTART:20200101T090000Z\nFREQ=YEARLY;BYEASTER=49;COMPUTUS=ORTHODOX"
+	r, err := StrToRRule(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := r.String(); s != str {
+		t.Errorf("get %q, want %q", s, str)
+	}
+	want := time.Date(2020, 6, 7, 9, 0, 0, 0, time.UTC)
+	if value := r.After(r.GetDTStart(), true); !value.Equal(want) {
+		t.Errorf("get %v, want %v", value, want)
+	}
+}
+
+func TestExpandedYearStr(t *testing.T) {
+	for _, str := range []string{
+		"DTSTART:-00050301T090000Z\nFREQ=YEARLY;UNTIL=00020301T090000Z",
+		"DTSTART;TZID=America/New_York:120000229T090000\nFREQ=MONTHLY;UNTIL=120001231T140000Z",
+	} {
+		r, err := StrToRRule(str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := r.String(); s != str {
+			t.Errorf("get %q, want %q", s, str)
+		}
+	}
+	if _, err := StrToRRule("DTSTART:100010229T090000Z\nFREQ=YEARLY"); err == nil {
+		t.Errorf("get nil, want error for February 29 of 10001")
+	}
+}
+
 func TestInvalidString(t *testing.T) {
 	cases := []string{
 		"",
@@ -62,6 +96,7 @@ func TestInvalidString(t *testing.T) {
 		"FREQ=WEEKLY;BYDAY=M",
 		"FREQ=WEEKLY;BYDAY=MQ",
 		"FREQ=WEEKLY;BYDAY=+MO",
+		"FREQ=YEARLY;BYEASTER=0;COMPUTUS=JULIAN",
 		"BYDAY=MO",
 	}
 	for _, item := range cases {
@@ -413,3 +448,149 @@ func TestStrSetParseErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestParseErrorLocation(t *testing.T) {
+	cases := []struct {
+		ss   []string
+		want ParseError
+	}{
+		{[]string{"RRULE:FREQ=WEEKLY;BYDAY=MO,XX"},
+			ParseError{Line: 1, Property: "RRULE", Param: "BYDAY", Offset: 27, Value: "XX", Kind: ErrInvalidValue}},
+		{[]string{"DTSTART:19970902T090000Z", "RRULE:FREQ=DAILY;HELLO=WORLD"},
+			ParseError{Line: 2, Property: "RRULE", Offset: 17, Value: "HELLO", Kind: ErrUnknownProperty}},
+		{[]string{"RRULE:FREQ=DAILY;BYMONTH="},
+			ParseError{Line: 1, Property: "RRULE", Param: "BYMONTH", Offset: 17, Kind: ErrMissingValue}},
+		{[]string{"RRULE:COUNT=2"},
+			ParseError{Line: 1, Property: "RRULE", Param: "FREQ", Offset: 6, Kind: ErrMissingProperty}},
+		{[]string{"RRULE:FREQ=DAILY;BYSETPOS=0"},
+			ParseError{Line: 1, Property: "RRULE", Param: "BYSETPOS", Offset: 26, Value: "0", Kind: ErrOutOfRange}},
+		{[]string{"RRULE:FREQ=DAILY;BYSETPOS=1,400"},
+			ParseError{Line: 1, Property: "RRULE", Param: "BYSETPOS", Offset: 28, Value: "400", Kind: ErrOutOfRange}},
+		{[]string{"RRULE:FREQ=MONTHLY;BYDAY=MO,+60TU"},
+			ParseError{Line: 1, Property: "RRULE", Param: "BYDAY", Offset: 28, Value: "+60TU", Kind: ErrOutOfRange}},
+		{[]string{"DTSTART;TZID=Nowhere/Never:19970902T090000"},
+			ParseError{Line: 1, Property: "DTSTART", Param: "TZID", Offset: 8, Value: "TZID=Nowhere/Never", Kind: ErrInvalidValue}},
+		{[]string{"RRULE:FREQ=DAILY", "EXDATE:19970902T090000Z,1997"},
+			ParseError{Line: 2, Property: "EXDATE", Offset: 24, Value: "1997", Kind: ErrInvalidValue}},
+		{[]string{"RRULE:FREQ=DAILY", "FOO"},
+			ParseError{Line: 2, Value: "FOO", Kind: ErrSyntax}},
+	}
+	for _, c := range cases {
+		_, err := StrSliceToRRuleSet(c.ss)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("StrSliceToRRuleSet(%q) = %v, want *ParseError", c.ss, err)
+			continue
+		}
+		if !errors.Is(err, c.want.Kind) {
+			t.Errorf("StrSliceToRRuleSet(%q) = %v, want kind %v", c.ss, err, c.want.Kind)
+		}
+		got := *pe
+		got.Err = nil
+		if got != c.want {
+			t.Errorf("StrSliceToRRuleSet(%q) = %+v, want %+v", c.ss, got, c.want)
+		}
+	}
+}
+
+func TestRangeErrorLocation(t *testing.T) {
+	_, ruleErr := StrToRRule("DTSTART:19970902T090000Z\nFREQ=DAILY;BYSETPOS=400")
+	_, setErr := StrToRRuleSet("DTSTART:19970902T090000Z\nRRULE:FREQ=DAILY;BYSETPOS=400")
+	cases := []struct {
+		err  error
+		want ParseError
+	}{
+		{ruleErr, ParseError{Line: 2, Property: "RRULE", Param: "BYSETPOS", Offset: 20, Value: "400", Kind: ErrOutOfRange}},
+		{setErr, ParseError{Line: 2, Property: "RRULE", Param: "BYSETPOS", Offset: 26, Value: "400", Kind: ErrOutOfRange}},
+	}
+	for _, c := range cases {
+		var pe *ParseError
+		if !errors.As(c.err, &pe) {
+			t.Errorf("get %v, want *ParseError", c.err)
+			continue
+		}
+		got := *pe
+		got.Err = nil
+		if got != c.want {
+			t.Errorf("get %+v, want %+v", got, c.want)
+		}
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	_, err := StrToRRuleSet("DTSTART:19970902T090000Z\nRRULE:FREQ=DAILY;BYHOUR=24")
+	want := "line 2 RRULE: byhour must be between 0 and 23"
+	if err == nil || err.Error() != want {
+		t.Errorf("get %v, want %v", err, want)
+	}
+	_, err = StrToRRule("FREQ=DAILY;HELLO=WORLD")
+	want = "line 1 RRULE: unknown property: HELLO"
+	if err == nil || err.Error() != want {
+		t.Errorf("get %v, want %v", err, want)
+	}
+}
+
+func TestLenientROption(t *testing.T) {
+	cases := []struct {
+		str      string
+		want     string
+		warnings int
+	}{
+		{"FREQ=WEEKLY;;BYDAY=MO,", "FREQ=WEEKLY;BYDAY=MO", 2},
+		{"freq=weekly;byday=mo,we", "FREQ=WEEKLY;BYDAY=MO,WE", 4},
+		{"FREQ=weekly;BYDAY=MO", "FREQ=WEEKLY;BYDAY=MO", 1},
+		{"FREQ=DAILY;X-NAME=Standup;COUNT=3", "FREQ=DAILY;COUNT=3;X-NAME=Standup", 0},
+		{"FREQ=DAILY;X-Name=Standup;COUNT=3", "FREQ=DAILY;COUNT=3;X-Name=Standup", 0},
+		{"FREQ=DAILY;FOO=BAR;BYHOUR=9,X,10", "FREQ=DAILY;BYHOUR=9,10", 2},
+		{"RRULE:FREQ=DAILY;UNTIL=2018-01-01T00:00:00+01:00", "FREQ=DAILY;UNTIL=20171231T230000Z", 2},
+		{"FREQ=DAILY;UNTIL=20180101T000000+0100", "FREQ=DAILY;UNTIL=20171231T230000Z", 1},
+	}
+	for _, c := range cases {
+		option, warnings, err := StrToROptionWithOptions(c.str, ParseOptions{Mode: Lenient})
+		if err != nil {
+			t.Errorf("StrToROptionWithOptions(%q) returned error: %v", c.str, err)
+			continue
+		}
+		if value := option.RRuleString(); value != c.want {
+			t.Errorf("StrToROptionWithOptions(%q) = %v, want %v", c.str, value, c.want)
+		}
+		if len(warnings) != c.warnings {
+			t.Errorf("StrToROptionWithOptions(%q) warnings = %v, want %d", c.str, warnings, c.warnings)
+		}
+		if _, _, err := StrToROptionWithOptions(c.str, ParseOptions{}); err == nil {
+			t.Errorf("StrToROptionWithOptions(%q) in strict mode = nil, want error", c.str)
+		}
+	}
+}
+
+func TestLenientWarningLocation(t *testing.T) {
+	_, warnings, err := StrToRRuleSetWithOptions("DTSTART:19970902T090000Z\n\nRRULE:FREQ=DAILY;BYDAY=MO,,TU\nX-FOO:bar",
+		ParseOptions{Mode: Lenient})
+	if err != nil {
+		t.Fatalf("StrToRRuleSetWithOptions returned error: %v", err)
+	}
+	want := []ParseError{
+		{Line: 2, Kind: ErrSyntax},
+		{Line: 3, Property: "RRULE", Param: "BYDAY", Offset: 26, Kind: ErrSyntax},
+		{Line: 4, Property: "X-FOO", Value: "X-FOO", Kind: ErrUnknownProperty},
+	}
+	if len(warnings) != len(want) {
+		t.Fatalf("get %v, want %d warnings", warnings, len(want))
+	}
+	for i, w := range warnings {
+		got := *w
+		got.Err = nil
+		if got != want[i] {
+			t.Errorf("get %+v, want %+v", got, want[i])
+		}
+	}
+}
+
+func TestLenientSetErrors(t *testing.T) {
+	if _, _, err := StrToRRuleSetWithOptions("RRULE:BYDAY=MO", ParseOptions{Mode: Lenient}); !errors.Is(err, ErrMissingProperty) {
+		t.Errorf("get %v, want ErrMissingProperty", err)
+	}
+	if _, _, err := StrToRRuleSetWithOptions("  ", ParseOptions{Mode: Lenient}); err == nil {
+		t.Error("get nil, want error")
+	}
+}
@@ -0,0 +1,154 @@
+package rrule
+
+import (
+	"sort"
+	"time"
+)
+
+// TimelineEntry is an occurrence of a Timeline, labeled with the key of the
+// source it came from.
+type TimelineEntry struct {
+	Key  string
+	Time time.Time
+}
+
+// Timeline merges the occurrences of keyed RRules and Sets in chronological
+// order, keeping track of which source each occurrence came from.
+// Simultaneous occurrences come in the order the sources were added.
+// Like Set, iterating does not modify a Timeline, but it must not be changed
+// while it is iterated.
+type Timeline struct {
+	sources []timelineSource
+}
+
+type timelineSource struct {
+	key      string
+	iterator func() Next
+	exdate   []time.Time
+}
+
+// AddRRule adds the occurrences of r under key, replacing any source already
+// added with that key.
+func (t *Timeline) AddRRule(key string, r *RRule) {
+	t.add(key, r.Iterator)
+}
+
+// AddSet adds the occurrences of set under key, replacing any source already
+// added with that key.
+func (t *Timeline) AddSet(key string, set *Set) {
+	t.add(key, func() Next { return set.Iterator() })
+}
+
+func (t *Timeline) add(key string, iterator func() Next) {
+	if i := t.index(key); i >= 0 {
+		t.sources[i].iterator = iterator
+		return
+	}
+	t.sources = append(t.sources, timelineSource{key: key, iterator: iterator})
+}
+
+// Remove removes the source with the given key, along with its exclusions.
+func (t *Timeline) Remove(key string) {
+	if i := t.index(key); i >= 0 {
+		t.sources = append(t.sources[:i], t.sources[i+1:]...)
+	}
+}
+
+// Exclude excludes the given occurrences of the source with the given key
+// from the timeline. It does nothing if there is no such source.
+func (t *Timeline) Exclude(key string, exdate ...time.Time) {
+	if i := t.index(key); i >= 0 {
+		s := &t.sources[i]
+		s.exdate = append(s.exdate, exdate...)
+		sort.Sort(timeSlice(s.exdate))
+	}
+}
+
+// Keys returns the keys of the sources in the order they were added.
+func (t *Timeline) Keys() []string {
+	keys := make([]string, len(t.sources))
+	for i, s := range t.sources {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+func (t *Timeline) index(key string) int {
+	for i, s := range t.sources {
+		if s.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Iterator returns an iterator over the entries of the timeline.
+func (t *Timeline) Iterator() (next func() (TimelineEntry, bool)) {
+	gens := make([]Next, len(t.sources))
+	keys := make([]string, len(t.sources))
+	for i, s := range t.sources {
+		gens[i] = excluding(s.iterator(), s.exdate)
+		keys[i] = s.key
+	}
+	h := newGenHeap(gens...)
+	return func() (TimelineEntry, bool) {
+		dt, source, ok := h.nextSource()
+		if !ok {
+			return TimelineEntry{}, false
+		}
+		return TimelineEntry{keys[source], dt}, true
+	}
+}
+
+// excluding returns the times of next that are not in the sorted exdate.
+func excluding(next Next, exdate []time.Time) Next {
+	return func() (time.Time, bool) {
+		for {
+			dt, ok := next()
+			if !ok {
+				return dt, false
+			}
+			for len(exdate) != 0 && exdate[0].Before(dt) {
+				exdate = exdate[1:]
+			}
+			if len(exdate) == 0 || !exdate[0].Equal(dt) {
+				return dt, true
+			}
+		}
+	}
+}
+
+// Between returns the entries of the timeline between after and before.
+// The inc keyword defines what happens if after and/or before are themselves
+// occurrences. With inc == True, they will be included in the list, if they
+// are found in the recurrence set.
+func (t *Timeline) Between(after, before time.Time, inc bool) []TimelineEntry {
+	entries := []TimelineEntry{}
+	next := t.Iterator()
+	for {
+		e, ok := next()
+		if !ok || inc && e.Time.After(before) || !inc && !e.Time.Before(before) {
+			return entries
+		}
+		if inc && !e.Time.Before(after) || !inc && e.Time.After(after) {
+			entries = append(entries, e)
+		}
+	}
+}
+
+// After returns the first entry after the given datetime instance, or the
+// zero TimelineEntry if there is none.
+// The inc keyword defines what happens if dt is an occurrence.
+// With inc == True, if dt itself is an occurrence, it will be returned.
+func (t *Timeline) After(dt time.Time, inc bool) TimelineEntry {
+	next := t.Iterator()
+	for {
+		e, ok := next()
+		if !ok {
+			return TimelineEntry{}
+		}
+		if inc && !e.Time.Before(dt) || !inc && e.Time.After(dt) {
+			return e
+		}
+	}
+}
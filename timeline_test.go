@@ -0,0 +1,46 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeline(t *testing.T) {
+	daily, _ := NewRRule(ROption{Freq: DAILY, Count: 3, Dtstart: time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)})
+	set := &Set{}
+	set.RDate(time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC))
+	set.RDate(time.Date(2020, 1, 2, 8, 0, 0, 0, time.UTC))
+
+	tl := &Timeline{}
+	tl.AddRRule("standup", daily)
+	tl.AddSet("review", set)
+	tl.Exclude("standup", time.Date(2020, 1, 3, 9, 0, 0, 0, time.UTC))
+
+	want := []TimelineEntry{
+		{"standup", time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)},
+		{"review", time.Date(2020, 1, 2, 8, 0, 0, 0, time.UTC)},
+		{"standup", time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{"review", time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC)},
+	}
+	got := tl.Between(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC), true)
+	if len(got) != len(want) {
+		t.Fatalf("get %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i].Key != want[i].Key || !got[i].Time.Equal(want[i].Time) {
+			t.Errorf("get %v, want %v", got[i], want[i])
+		}
+	}
+
+	if e := tl.After(time.Date(2020, 1, 2, 8, 0, 0, 0, time.UTC), false); e != want[2] {
+		t.Errorf("get %v, want %v", e, want[2])
+	}
+	if e := tl.After(time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC), true); !e.Time.IsZero() {
+		t.Errorf("get %v, want zero", e)
+	}
+
+	tl.Remove("standup")
+	if keys := tl.Keys(); len(keys) != 1 || keys[0] != "review" {
+		t.Errorf("get %v, want [review]", keys)
+	}
+}
@@ -3,14 +3,28 @@ package rrule
 import (
 	"errors"
 	"math"
+	"sort"
 	"time"
 )
 
-// MAXYEAR
+// MAXYEAR is the last year with four digits, the most RFC 5545 strings can
+// hold. Rules are not limited to it.
 const (
 	MAXYEAR = 9999
 )
 
+// defaultUntilYears is how long a rule without COUNT and UNTIL lasts, about
+// as long as the largest time.Duration.
+const defaultUntilYears = 290
+
+// gregorianCycle is the length in years of the cycle of the Gregorian
+// calendar, and easterGap the longest a date of Western Easter goes without
+// recurring in a leap or a common year.
+const (
+	gregorianCycle = 400
+	easterGap      = 5600
+)
+
 // Next is a generator of time.Time.
 // It returns false of Ok if there is no value to generate.
 type Next func() (value time.Time, ok bool)
@@ -50,6 +64,11 @@ func pymod(a, b int) int {
 	return r
 }
 
+// pydiv returns a/b rounded toward negative infinity, as Python's // does.
+func pydiv(a, b int) int {
+	return (a - pymod(a, b)) / b
+}
+
 // divmod in Python
 func divmod(a, b int) (div, mod int) {
 	return int(math.Floor(float64(a) / float64(b))), pymod(a, b)
@@ -81,6 +100,18 @@ func repeat(value, count int) []int {
 	return result
 }
 
+// zeroed returns a slice of n zeros, reusing the array of s if possible.
+func zeroed(s []int, n int) []int {
+	if cap(s) < n {
+		return make([]int, n)
+	}
+	s = s[:n]
+	for i := range s {
+		s[i] = 0
+	}
+	return s
+}
+
 func concat(slices ...[]int) []int {
 	result := []int{}
 	for _, item := range slices {
@@ -107,6 +138,28 @@ func pySubscript(slice []int, index int) (int, error) {
 	return slice[index], nil
 }
 
+// sortTimes sorts a short slice in place. Unlike sort.Sort, it does not
+// allocate.
+func sortTimes(s []time.Time) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && s[j].Before(s[j-1]); j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+}
+
+// sortedTimes returns s if it is sorted, or else a sorted copy of s, so that
+// iterating does not modify a shared slice.
+func sortedTimes(s []time.Time) []time.Time {
+	if sort.IsSorted(timeSlice(s)) {
+		return s
+	}
+	sorted := make([]time.Time, len(s))
+	copy(sorted, s)
+	sort.Sort(timeSlice(sorted))
+	return sorted
+}
+
 func timeSliceIterator(s []time.Time) func() (time.Time, bool) {
 	index := 0
 	return func() (time.Time, bool) {
@@ -119,12 +172,24 @@ func timeSliceIterator(s []time.Time) func() (time.Time, bool) {
 	}
 }
 
-func easter(year int) time.Time {
-	g := year % 19
-	c := year / 100
-	h := (c - c/4 - (8*c+13)/25 + 19*g + 15) % 30
+// easter returns the date of Easter Sunday of year in the proleptic
+// Gregorian calendar, as dateutil's easter does.
+func easter(year int, computus Computus) time.Time {
+	g := pymod(year, 19)
+	if computus == Orthodox {
+		i := (19*g + 15) % 30
+		j := pymod(year+pydiv(year, 4)+i, 7)
+		p := i - j
+		d := 1 + (p+27+(p+6)/40)%31
+		m := 3 + (p+26)/30
+		// Days between the Julian and the Gregorian calendars.
+		e := pydiv(year, 100) - pydiv(year, 400) - 2
+		return time.Date(year, time.Month(m), d+e, 0, 0, 0, 0, time.UTC)
+	}
+	c := pydiv(year, 100)
+	h := pymod(c-pydiv(c, 4)-pydiv(8*c+13, 25)+19*g+15, 30)
 	i := h - (h/28)*(1-(h/28)*(29/(h+1))*((21-g)/11))
-	j := (year + year/4 + i + 2 - c + c/4) % 7
+	j := pymod(year+pydiv(year, 4)+i+2-c+pydiv(c, 4), 7)
 	p := i - j
 	d := 1 + (p+27+(p+6)/40)%31
 	m := 3 + (p+26)/30
@@ -143,14 +208,17 @@ func all(next Next) []time.Time {
 }
 
 func between(next Next, after, before time.Time, inc bool) []time.Time {
-	result := []time.Time{}
+	return appendBetween([]time.Time{}, next, after, before, inc)
+}
+
+func appendBetween(dst []time.Time, next Next, after, before time.Time, inc bool) []time.Time {
 	for {
 		v, ok := next()
 		if !ok || inc && v.After(before) || !inc && !v.Before(before) {
-			return result
+			return dst
 		}
 		if inc && !v.Before(after) || !inc && v.After(after) {
-			result = append(result, v)
+			dst = append(dst, v)
 		}
 	}
 }
@@ -182,3 +250,60 @@ type optInt struct {
 	Int     int
 	Defined bool
 }
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func lcm(a, b int64) int64 {
+	return a / gcd(a, b) * b
+}
+
+func count(next Next) int {
+	n := 0
+	for _, ok := next(); ok; _, ok = next() {
+		n++
+	}
+	return n
+}
+
+func countBetween(next Next, after, before time.Time, inc bool) int {
+	n := 0
+	for {
+		v, ok := next()
+		if !ok || inc && v.After(before) || !inc && !v.Before(before) {
+			return n
+		}
+		if inc && !v.Before(after) || !inc && v.After(after) {
+			n++
+		}
+	}
+}
+
+func nth(next Next, i int) (time.Time, bool) {
+	if i < 0 {
+		return time.Time{}, false
+	}
+	for {
+		v, ok := next()
+		if !ok || i == 0 {
+			return v, ok
+		}
+		i--
+	}
+}
+
+func indexOf(next Next, dt time.Time) (int, bool) {
+	for i := 0; ; i++ {
+		v, ok := next()
+		if !ok || v.After(dt) {
+			return 0, false
+		}
+		if v.Equal(dt) {
+			return i, true
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package rrule
+
+import (
+	"errors"
+	"time"
+)
+
+// ZoneMode tells how InLocation moves a recurrence to another time zone.
+type ZoneMode int
+
+// Zone modes
+const (
+	// KeepWallClock keeps the wall clock of every occurrence, e.g. 9:00 in
+	// the old zone becomes 9:00 in the new one.
+	KeepWallClock ZoneMode = iota
+	// KeepInstants keeps the instant of every occurrence, rewriting the
+	// BY* parts for the wall clock of the new zone.
+	KeepInstants
+)
+
+// ErrOffsetsDiffer is returned by InLocation with KeepInstants when the
+// difference between the UTC offsets of the zones changes over the
+// recurrence, e.g. between a zone with daylight saving time and one
+// without, so that no rule in the new zone has the same instants.
+var ErrOffsetsDiffer = errors.New("the difference between the UTC offsets changes")
+
+// offsetCheckYears bounds the check of the UTC offsets of rules without
+// UNTIL.
+const offsetCheckYears = 100
+
+// inWallClock returns the time with the wall clock of t in loc.
+func inWallClock(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	return time.Date(year, month, day, hour, minute, second, t.Nanosecond(), loc)
+}
+
+// offsetDelta returns how much later the wall clock of loc is than the one
+// of from at t.
+func offsetDelta(t time.Time, from, loc *time.Location) time.Duration {
+	_, fromOffset := t.In(from).Zone()
+	_, offset := t.In(loc).Zone()
+	return time.Duration(offset-fromOffset) * time.Second
+}
+
+// constantOffsetDelta returns the difference between the UTC offsets of loc
+// and from, and whether it stays the same for the occurrences of r up to
+// end. It compares the offsets daily, and on the days either zone changes
+// its offset, at the times of day of the rule, or every quarter of an hour
+// for rules finer than DAILY.
+func constantOffsetDelta(r *RRule, end time.Time, loc *time.Location) (time.Duration, bool) {
+	from := r.dtstart.Location()
+	delta := offsetDelta(r.dtstart, from, loc)
+	_, fromOffset := r.dtstart.In(from).Zone()
+	_, offset := r.dtstart.In(loc).Zone()
+	for day := r.dtstart; day.Before(end); day = day.Add(24 * time.Hour) {
+		next := day.Add(24 * time.Hour)
+		_, nextFromOffset := next.In(from).Zone()
+		_, nextOffset := next.In(loc).Zone()
+		if nextFromOffset == fromOffset && nextOffset == offset {
+			continue
+		}
+		fromOffset, offset = nextFromOffset, nextOffset
+		year, month, date := day.In(from).Date()
+		for d := date - 1; d <= date+2; d++ {
+			if r.freq >= HOURLY {
+				start := time.Date(year, month, d, 0, 0, 0, 0, from)
+				for t := start; t.Before(start.AddDate(0, 0, 1)); t = t.Add(time.Hour / 4) {
+					if offsetDelta(t, from, loc) != delta {
+						return delta, false
+					}
+				}
+				continue
+			}
+			for _, clock := range r.timeset {
+				t := time.Date(year, month, d, clock.Hour(), clock.Minute(), clock.Second(), 0, from)
+				if offsetDelta(t, from, loc) != delta {
+					return delta, false
+				}
+			}
+		}
+	}
+	return delta, true
+}
+
+// InLocation returns a copy of the rule in loc, leaving r unchanged. With
+// KeepWallClock, DTSTART and UNTIL keep their wall clock. With
+// KeepInstants, the occurrences keep their instants, which requires the
+// UTC offsets of both zones to differ by the same amount at the times of the
+// occurrences up to UNTIL, or 100 years after DTSTART; the BY* parts are
+// rewritten as Shift does.
+func (r *RRule) InLocation(loc *time.Location, mode ZoneMode) (*RRule, error) {
+	option, err := r.optionInLocation(loc, mode)
+	if err != nil {
+		return nil, err
+	}
+	return r.with(option), nil
+}
+
+func (r *RRule) optionInLocation(loc *time.Location, mode ZoneMode) (ROption, error) {
+	option := r.OrigOptions
+	from := r.dtstart.Location()
+	end := r.dtstart.AddDate(offsetCheckYears, 0, 0)
+	if until := option.Until; !until.IsZero() {
+		if until.Before(end) {
+			end = until
+		}
+		option.Until = inWallClock(until.In(from), loc).In(until.Location())
+	}
+	option.Dtstart = inWallClock(r.dtstart, loc)
+	if mode == KeepWallClock {
+		return option, nil
+	}
+
+	delta, ok := constantOffsetDelta(r, end, loc)
+	if !ok {
+		return option, ErrOffsetsDiffer
+	}
+	return ShiftROption(option, delta)
+}
+
+// InLocation returns a copy of the set in loc, leaving set unchanged. Its
+// RRULE moves as RRule.InLocation does; with KeepWallClock, RDATEs and
+// EXDATEs keep their wall clock in the location of DTSTART, and with
+// KeepInstants, they keep their instants.
+func (set *Set) InLocation(loc *time.Location, mode ZoneMode) (*Set, error) {
+	c := set.clone()
+	from := time.UTC
+	if !set.dtstart.IsZero() {
+		from = set.dtstart.Location()
+	}
+	if c.rrule != nil {
+		from = c.rrule.dtstart.Location()
+		rrule, err := c.rrule.InLocation(loc, mode)
+		if err != nil {
+			return nil, err
+		}
+		c.rrule = rrule
+	}
+
+	move := func(t time.Time) time.Time {
+		if mode == KeepInstants {
+			return t.In(loc)
+		}
+		return c.truncate(inWallClock(t.In(from), loc))
+	}
+	if !c.dtstart.IsZero() {
+		c.dtstart = move(c.dtstart)
+		if c.rrule != nil {
+			c.dtstart = c.rrule.dtstart
+		}
+	}
+	for i, rdate := range c.rdate {
+		c.rdate[i] = move(rdate)
+	}
+	for i, exdate := range c.exdate {
+		c.exdate[i] = move(exdate)
+	}
+	return c, nil
+}
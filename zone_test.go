@@ -0,0 +1,68 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetInLocation(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	la, _ := time.LoadLocation("America/Los_Angeles")
+	set, err := StrToRRuleSet(`DTSTART;TZID=America/New_York:20200302T090000
+RRULE:FREQ=WEEKLY;COUNT=40;BYDAY=MO,TU,WE,TH,FR;BYHOUR=9
+EXDATE;TZID=America/New_York:20200304T090000`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := set.All()
+
+	wall, err := set.InLocation(paris, KeepWallClock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := wall.All()
+	if len(got) != len(all) {
+		t.Fatalf("get %d occurrences, want %d", len(got), len(all))
+	}
+	for i := range got {
+		if want := inWallClock(all[i], paris); !got[i].Equal(want) || got[i].Location() != paris {
+			t.Errorf("get %v, want %v", got[i], want)
+		}
+	}
+
+	instants, err := set.InLocation(la, KeepInstants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, dt := range instants.All() {
+		if !dt.Equal(all[i]) {
+			t.Errorf("get %v, want %v", dt, all[i])
+		}
+	}
+	if got := instants.GetRRule().OrigOptions.RRuleString(); got != "FREQ=WEEKLY;COUNT=40;BYDAY=MO,TU,WE,TH,FR;BYHOUR=6" {
+		t.Errorf("get %v", got)
+	}
+
+	if _, err := set.InLocation(time.UTC, KeepInstants); !errors.Is(err, ErrOffsetsDiffer) {
+		t.Errorf("get %v, want %v", err, ErrOffsetsDiffer)
+	}
+}
+
+func TestRRuleInLocation(t *testing.T) {
+	phoenix, _ := time.LoadLocation("America/Phoenix")
+	r, _ := NewRRule(ROption{Freq: DAILY, Count: 10, Dtstart: time.Date(2020, 1, 1, 20, 0, 0, 0, phoenix)})
+	utc, err := r.InLocation(time.UTC, KeepInstants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, want := utc.All(), r.All()
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("get %v, want %v", got[i], want[i])
+		}
+	}
+	if got := utc.GetDTStart(); got.Location() != time.UTC || got.Hour() != 3 || got.Day() != 2 {
+		t.Errorf("get %v", got)
+	}
+}